@@ -4,9 +4,16 @@ import (
 	"context"
 
 	"github.com/dennwc/metasearch/base"
+	"github.com/dennwc/metasearch/search"
 )
 
+type Request struct {
+	Text   string
+	Lang   search.LangCode
+	Region search.RegionCode
+}
+
 type Service interface {
 	base.Provider
-	AutoComplete(ctx context.Context, text string) ([]string, error)
+	AutoComplete(ctx context.Context, req Request) ([]string, error)
 }
@@ -5,13 +5,17 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dennwc/metasearch"
+	"github.com/dennwc/metasearch/autocomplete"
 	_ "github.com/dennwc/metasearch/providers/all"
 	"github.com/dennwc/metasearch/search"
+	"github.com/dennwc/metasearch/server"
 )
 
 var Root = &cobra.Command{
@@ -64,7 +68,7 @@ func init() {
 			if err != nil {
 				return err
 			}
-			list, err := s.AutoComplete(ctx, qu)
+			list, err := s.AutoComplete(ctx, autocomplete.Request{Text: qu})
 			if err != nil {
 				return err
 			}
@@ -75,6 +79,38 @@ func init() {
 		},
 	}
 	Root.AddCommand(cmdAutoc)
+
+	cmdServe := &cobra.Command{
+		Use:   "serve",
+		Short: "serves the HTTP API that can be federated by other instances",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			var (
+				s   *metasearch.Engine
+				err error
+			)
+			if priv, _ := cmd.Flags().GetBool("private"); priv {
+				s, err = metasearch.NewEngine(ctx)
+			} else {
+				s, err = metasearch.NewPublicEngine(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			addr, _ := cmd.Flags().GetString("addr")
+			node, _ := cmd.Flags().GetString("node")
+			if node == "" {
+				node, _ = os.Hostname()
+			}
+			srv := server.New(node, s)
+			log.Printf("node %s: serving on %s", srv.Node, addr)
+			return http.ListenAndServe(addr, srv)
+		},
+	}
+	cmdServe.Flags().String("addr", "127.0.0.1:8080", "address to listen on")
+	cmdServe.Flags().Bool("private", false, "also serve private providers, such as local files, browsing history and plugins")
+	cmdServe.Flags().String("node", "", "unique ID of this node (defaults to the host name)")
+	Root.AddCommand(cmdServe)
 }
 
 func main() {
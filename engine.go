@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/dennwc/metasearch/autocomplete"
 	"github.com/dennwc/metasearch/base"
@@ -13,8 +17,9 @@ import (
 )
 
 var (
-	_ search.Searcher      = (*Engine)(nil)
-	_ autocomplete.Service = (*Engine)(nil)
+	_ search.Searcher       = (*Engine)(nil)
+	_ autocomplete.Service  = (*Engine)(nil)
+	_ search.SourceIterator = (*multiIterator)(nil)
 )
 
 func NewEngine(ctx context.Context, provs ...base.Provider) (*Engine, error) {
@@ -23,19 +28,15 @@ func NewEngine(ctx context.Context, provs ...base.Provider) (*Engine, error) {
 		byID:  make(map[string]base.Provider),
 	}
 	if len(s.provs) == 0 {
-		s.provs = nil
-		for _, fnc := range providers.List() {
-			p, err := fnc(ctx)
-			if err != nil {
-				return nil, err
-			}
-			s.provs = append(s.provs, p)
+		var err error
+		s.provs, err = newProviders(ctx, providers.List())
+		if err != nil {
+			return nil, err
 		}
 	}
 	if len(s.provs) == 0 {
 		return nil, fmt.Errorf("none providers were selected")
 	}
-	// TODO: request supported languages, convert language from the request to the right one for this provider
 	for _, p := range s.provs {
 		s.byID[p.ID()] = p
 
@@ -45,28 +46,82 @@ func NewEngine(ctx context.Context, provs ...base.Provider) (*Engine, error) {
 		if pr, ok := p.(autocomplete.Service); ok {
 			s.autoc = append(s.autoc, pr)
 		}
+		if pr, ok := p.(search.DiscussionFinder); ok {
+			s.discuss = append(s.discuss, pr)
+		}
+		if pr, ok := p.(search.SnapshotFinder); ok {
+			s.archive = append(s.archive, pr)
+		}
 	}
+	// answers are always returned first
+	sort.SliceStable(s.search, func(i, j int) bool {
+		_, ai := s.search[i].(search.AnswerService)
+		_, aj := s.search[j].(search.AnswerService)
+		return ai && !aj
+	})
+	s.locales = loadLocales(ctx, s.search)
 	return s, nil
 }
 
+// NewPublicEngine is like NewEngine, but only uses registered providers which are not private.
+// It should be used when the engine is exposed to other users.
+func NewPublicEngine(ctx context.Context) (*Engine, error) {
+	provs, err := newProviders(ctx, providers.ListPublic())
+	if err != nil {
+		return nil, err
+	} else if len(provs) == 0 {
+		return nil, fmt.Errorf("none providers were selected")
+	}
+	return NewEngine(ctx, provs...)
+}
+
+// newProviders creates providers from the registry, skipping ones that are not configured.
+func newProviders(ctx context.Context, list []providers.ProviderFunc) ([]base.Provider, error) {
+	var out []base.Provider
+	for _, fnc := range list {
+		p, err := fnc(ctx)
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue // not configured
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
 type Engine struct {
 	provs []base.Provider
 	byID  map[string]base.Provider
 
-	search []search.Service
-	autoc  []autocomplete.Service
+	search  []search.Service
+	autoc   []autocomplete.Service
+	discuss []search.DiscussionFinder
+	archive []search.SnapshotFinder
+
+	locales map[string]*locale
 }
 
 func (s *Engine) ID() string {
 	return "meta"
 }
 
-func (s *Engine) AutoComplete(ctx context.Context, text string) ([]string, error) {
+func (s *Engine) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
 	var results []string
 	seen := make(map[string]struct{})
 	var last error
 	for _, p := range s.autoc {
-		arr, err := p.AutoComplete(ctx, text)
+		preq := req
+		loc := s.locales[p.ID()]
+		lang, ok := loc.matchLang(req.Lang)
+		if !ok {
+			continue
+		}
+		preq.Lang = lang
+		if preq.Region != (search.RegionCode{}) && !loc.hasRegion(preq.Region) {
+			preq.Region = search.RegionCode{}
+		}
+		arr, err := p.AutoComplete(ctx, preq)
 		if err != nil {
 			last = err
 			continue
@@ -82,11 +137,123 @@ func (s *Engine) AutoComplete(ctx context.Context, text string) ([]string, error
 	return results, last
 }
 
+// Discussions finds discussions of a given page using all providers that support it.
+// It can be used to enrich web search results with links to discussion threads.
+// Threads with more comments are returned first. An error is returned only if all providers failed.
+func (s *Engine) Discussions(ctx context.Context, u *url.URL) ([]*search.DiscussionResult, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		out    []*search.DiscussionResult
+		last   error
+		failed int
+	)
+	for _, p := range s.discuss {
+		wg.Add(1)
+		go func(p search.DiscussionFinder) {
+			defer wg.Done()
+			arr, err := p.Discussions(ctx, u)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("%s: %v", p.ID(), err)
+				last = err
+				failed++
+				return
+			}
+			out = append(out, arr...)
+		}(p)
+	}
+	wg.Wait()
+	if failed != 0 && failed == len(s.discuss) {
+		return nil, last
+	}
+	seen := make(map[string]struct{}, len(out))
+	uniq := out[:0]
+	for _, r := range out {
+		key := r.GetURL().String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		uniq = append(uniq, r)
+	}
+	sort.SliceStable(uniq, func(i, j int) bool {
+		return uniq[i].Comments > uniq[j].Comments
+	})
+	return uniq, nil
+}
+
+// Snapshot finds an archived copy of a page closest to a given time using all providers that support it.
+// If the time is zero, the latest copy is returned. It returns nil if the page was never archived.
+// An error is returned only if all providers failed.
+func (s *Engine) Snapshot(ctx context.Context, u *url.URL, at time.Time) (*search.Snapshot, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		best   *search.Snapshot
+		last   error
+		failed int
+	)
+	// better reports if snapshot a is closer to the requested time than b
+	better := func(a, b *search.Snapshot) bool {
+		if at.IsZero() {
+			return a.Time.After(b.Time)
+		}
+		da, db := a.Time.Sub(at), b.Time.Sub(at)
+		if da < 0 {
+			da = -da
+		}
+		if db < 0 {
+			db = -db
+		}
+		return da < db
+	}
+	for _, p := range s.archive {
+		wg.Add(1)
+		go func(p search.SnapshotFinder) {
+			defer wg.Done()
+			snap, err := p.Snapshot(ctx, u, at)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("%s: %v", p.ID(), err)
+				last = err
+				failed++
+				return
+			}
+			if snap != nil && (best == nil || better(snap, best)) {
+				best = snap
+			}
+		}(p)
+	}
+	wg.Wait()
+	if failed != 0 && failed == len(s.archive) {
+		return nil, last
+	}
+	return best, nil
+}
+
+// WithSnapshot attaches the latest archived copy of the result's page to it.
+// It can be used to provide a working link for pages that are no longer available.
+func (s *Engine) WithSnapshot(ctx context.Context, r search.Result) (*search.ArchivedResult, error) {
+	snap, err := s.Snapshot(ctx, r.GetURL(), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return &search.ArchivedResult{Result: r, Snapshot: snap}, nil
+}
+
 func (s *Engine) Search(ctx context.Context, req search.Request) search.ResultIterator {
 	its := make([]search.ResultIterator, 0, len(s.search))
 	ids := make([]string, 0, len(s.search))
 	for _, p := range s.search {
-		it := p.Search(ctx, req)
+		preq, ok := s.locales[p.ID()].localize(req)
+		if !ok {
+			log.Printf("%s: language %v is not supported", p.ID(), req.Lang)
+			continue
+		}
+		it := p.Search(ctx, preq)
 		if err := it.Err(); err != nil {
 			it.Close()
 			log.Println(err)
@@ -95,7 +262,19 @@ func (s *Engine) Search(ctx context.Context, req search.Request) search.ResultIt
 		its = append(its, it)
 		ids = append(ids, p.ID())
 	}
-	return &multiIterator{its: its, ids: ids, i: -1}
+	return &multiIterator{its: its, ids: ids, first: s.countAnswers(ids), i: -1}
+}
+
+// countAnswers returns the number of leading providers that implement search.AnswerService.
+func (s *Engine) countAnswers(ids []string) int {
+	n := 0
+	for _, id := range ids {
+		if _, ok := s.byID[id].(search.AnswerService); !ok {
+			break
+		}
+		n++
+	}
+	return n
 }
 
 func (s *Engine) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
@@ -129,17 +308,22 @@ func (s *Engine) ContinueSearch(ctx context.Context, tok search.Token) search.Re
 		it.its = append(it.its, sit)
 		it.ids = append(it.ids, pt.ID)
 	}
+	it.first = s.countAnswers(it.ids)
 	return it
 }
 
 type multiIterator struct {
-	its []search.ResultIterator
-	ids []string
-	i   int
-	err error
+	its   []search.ResultIterator
+	ids   []string
+	first int // number of leading iterators with answers, they are drained before other iterators
+	i     int
+	err   error
 }
 
 func (it *multiIterator) closeIter(i int) error {
+	if i < it.first {
+		it.first--
+	}
 	err := it.its[i].Close()
 	it.its = append(it.its[:i], it.its[i+1:]...)
 	it.ids = append(it.ids[:i], it.ids[i+1:]...)
@@ -155,6 +339,7 @@ func (it *multiIterator) NextPage(ctx context.Context) bool {
 				log.Println(err)
 			}
 			it.closeIter(i)
+			i--
 		}
 	}
 	return len(it.its) != 0
@@ -172,12 +357,23 @@ func (it *multiIterator) Next(ctx context.Context) bool {
 	if len(it.its) == 0 {
 		return false
 	}
-	if it.Buffered() == 0 {
-		if !it.NextPage(ctx) {
-			return false
+	for it.err == nil && it.first > 0 {
+		if it.its[0].Next(ctx) {
+			it.i = 0
+			return true
 		}
+		if err := it.its[0].Err(); err != nil {
+			log.Println(err)
+		}
+		it.closeIter(0)
+		it.i = -1
 	}
 	for it.err == nil && len(it.its) > 0 {
+		if it.Buffered() == 0 {
+			if !it.NextPage(ctx) {
+				return false
+			}
+		}
 		it.i++
 		if it.i >= len(it.its) {
 			it.i = 0
@@ -218,6 +414,14 @@ func (it *multiIterator) Result() search.Result {
 	return it.its[it.i].Result()
 }
 
+// Source returns the ID of the provider that returned the current result.
+func (it *multiIterator) Source() string {
+	if it.i < 0 || it.i >= len(it.ids) {
+		return ""
+	}
+	return it.ids[it.i]
+}
+
 func (it *multiIterator) Token() search.Token {
 	if len(it.its) == 0 {
 		return nil
@@ -0,0 +1,310 @@
+package metasearch
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+var _ search.Service = (*fakeService)(nil)
+
+type fakeService struct {
+	id      string
+	langs   []search.Language
+	regions []search.Region
+	results []string
+
+	last *search.Request
+}
+
+func (s *fakeService) ID() string {
+	return s.id
+}
+
+func (s *fakeService) Languages(ctx context.Context) ([]search.Language, error) {
+	return s.langs, nil
+}
+
+func (s *fakeService) Regions(ctx context.Context) ([]search.Region, error) {
+	return s.regions, nil
+}
+
+func (s *fakeService) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	s.last = &req
+	var page []search.Result
+	for _, v := range s.results {
+		page = append(page, &search.LinkResult{URL: url.URL{Scheme: "https", Host: v}, Title: v})
+	}
+	return &fakeIter{page: page, i: -1}
+}
+
+func (s *fakeService) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	return search.Empty{}
+}
+
+type fakeIter struct {
+	search.Empty
+	page []search.Result
+	i    int
+	done bool
+}
+
+func (it *fakeIter) NextPage(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	it.done = true
+	return len(it.page) != 0
+}
+
+func (it *fakeIter) Buffered() int {
+	if !it.done {
+		return 0
+	}
+	return len(it.page) - (it.i + 1)
+}
+
+func (it *fakeIter) Next(ctx context.Context) bool {
+	if it.Buffered() == 0 && !it.NextPage(ctx) {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		return false
+	}
+	it.i++
+	return true
+}
+
+func (it *fakeIter) Result() search.Result {
+	return it.page[it.i]
+}
+
+func langs(codes ...string) []search.Language {
+	var out []search.Language
+	for _, c := range codes {
+		out = append(out, search.Language{Code: search.MustParseLangCode(c), Name: c})
+	}
+	return out
+}
+
+func TestEngineLanguages(t *testing.T) {
+	ctx := context.Background()
+	any := &fakeService{id: "any", results: []string{"any.com"}}
+	de := &fakeService{
+		id: "de", results: []string{"de.com"},
+		langs:   langs("en-US", "de-DE", "de-AT"),
+		regions: []search.Region{{Code: search.MustParseRegionCode("DE")}},
+	}
+	en := &fakeService{
+		id: "en", results: []string{"en.com"},
+		langs: langs("en"),
+	}
+	s, err := NewEngine(ctx, any, de, en)
+	require.NoError(t, err)
+
+	it := s.Search(ctx, search.Request{
+		Query:  "solar",
+		Lang:   search.MustParseLangCode("de"),
+		Region: search.MustParseRegionCode("CH"),
+	})
+	defer it.Close()
+	var got []string
+	for it.Next(ctx) {
+		got = append(got, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.ElementsMatch(t, []string{"any.com", "de.com"}, got)
+
+	require.NotNil(t, any.last)
+	require.Equal(t, "de", any.last.Lang.String())
+	require.Equal(t, "CH", any.last.Region.String())
+
+	require.NotNil(t, de.last)
+	require.Equal(t, "de-DE", de.last.Lang.String())
+	require.Equal(t, search.RegionCode{}, de.last.Region)
+
+	require.Nil(t, en.last)
+
+	// the region is taken from the requested language
+	brave := &fakeService{
+		id: "brave", results: []string{"brave.com"},
+		langs:   langs("de", "en"),
+		regions: []search.Region{{Code: search.MustParseRegionCode("DE")}, {Code: search.MustParseRegionCode("AT")}},
+	}
+	s, err = NewEngine(ctx, brave)
+	require.NoError(t, err)
+	it = s.Search(ctx, search.Request{Query: "solar", Lang: search.MustParseLangCode("de-AT")})
+	for it.Next(ctx) {
+	}
+	require.NoError(t, it.Err())
+	it.Close()
+	require.NotNil(t, brave.last)
+	require.Equal(t, "de", brave.last.Lang.String())
+	require.Equal(t, "AT", brave.last.Region.String())
+}
+
+var _ search.DiscussionFinder = (*fakeForum)(nil)
+
+type fakeForum struct {
+	fakeService
+	threads map[string][]int // URL -> comment counts
+}
+
+func (s *fakeForum) Discussions(ctx context.Context, u *url.URL) ([]*search.DiscussionResult, error) {
+	var out []*search.DiscussionResult
+	for i, n := range s.threads[u.String()] {
+		out = append(out, &search.DiscussionResult{
+			LinkResult: search.LinkResult{
+				URL: url.URL{Scheme: "https", Host: s.id, Path: "/" + strconv.Itoa(i)},
+			},
+			Forum:    s.id,
+			Comments: n,
+		})
+	}
+	return out, nil
+}
+
+func TestEngineDiscussions(t *testing.T) {
+	ctx := context.Background()
+	const page = "https://go.dev/blog/go1.21"
+	hn := &fakeForum{
+		fakeService: fakeService{id: "hn"},
+		threads:     map[string][]int{page: {10, 200}},
+	}
+	reddit := &fakeForum{
+		fakeService: fakeService{id: "reddit"},
+		threads:     map[string][]int{page: {50}},
+	}
+	s, err := NewEngine(ctx, hn, reddit, &fakeService{id: "web"})
+	require.NoError(t, err)
+
+	u, err := url.Parse(page)
+	require.NoError(t, err)
+	got, err := s.Discussions(ctx, u)
+	require.NoError(t, err)
+	var urls []string
+	for _, r := range got {
+		urls = append(urls, r.GetURL().String())
+	}
+	require.Equal(t, []string{"https://hn/1", "https://reddit/0", "https://hn/0"}, urls)
+
+	u.Path = "/other"
+	got, err = s.Discussions(ctx, u)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+var _ search.SnapshotFinder = (*fakeArchive)(nil)
+
+type fakeArchive struct {
+	fakeService
+	captures map[string]time.Time // URL -> capture time
+}
+
+func (s *fakeArchive) Snapshot(ctx context.Context, u *url.URL, at time.Time) (*search.Snapshot, error) {
+	t, ok := s.captures[u.String()]
+	if !ok {
+		return nil, nil
+	}
+	return &search.Snapshot{
+		URL:      &url.URL{Scheme: "https", Host: s.id, Path: "/" + t.Format("2006") + "/" + u.String()},
+		Original: u,
+		Time:     t,
+		Status:   200,
+	}, nil
+}
+
+func TestEngineSnapshot(t *testing.T) {
+	ctx := context.Background()
+	const page = "https://example.com/"
+	year := func(y int) time.Time {
+		return time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	a := &fakeArchive{
+		fakeService: fakeService{id: "a"},
+		captures:    map[string]time.Time{page: year(2010)},
+	}
+	b := &fakeArchive{
+		fakeService: fakeService{id: "b"},
+		captures:    map[string]time.Time{page: year(2005)},
+	}
+	s, err := NewEngine(ctx, a, b, &fakeService{id: "web"})
+	require.NoError(t, err)
+
+	u, err := url.Parse(page)
+	require.NoError(t, err)
+	snap, err := s.Snapshot(ctx, u, year(2006))
+	require.NoError(t, err)
+	require.Equal(t, "https://b/2005/"+page, snap.URL.String())
+
+	r, err := s.WithSnapshot(ctx, &search.LinkResult{URL: *u, Title: "Example"})
+	require.NoError(t, err)
+	require.Equal(t, "Example", r.GetTitle())
+	require.Equal(t, "https://a/2010/"+page, r.Snapshot.URL.String())
+
+	u.Path = "/other"
+	snap, err = s.Snapshot(ctx, u, time.Time{})
+	require.NoError(t, err)
+	require.Nil(t, snap)
+}
+
+var _ search.AnswerService = (*fakeAnswers)(nil)
+
+type fakeAnswers struct {
+	fakeService
+	answer string
+}
+
+func (s *fakeAnswers) Answer(ctx context.Context, req search.Request) (*search.AnswerResult, error) {
+	return &search.AnswerResult{LinkResult: search.LinkResult{Title: s.answer}, Kind: "calc", Value: s.answer}, nil
+}
+
+func (s *fakeAnswers) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r, _ := s.Answer(ctx, req)
+	return &fakeIter{page: []search.Result{r}, i: -1}
+}
+
+func TestEngineAnswersFirst(t *testing.T) {
+	ctx := context.Background()
+	a := &fakeService{id: "a", results: []string{"a1.com", "a2.com"}}
+	b := &fakeService{id: "b", results: []string{"b1.com"}}
+	ans := &fakeAnswers{fakeService: fakeService{id: "ans"}, answer: "42"}
+	s, err := NewEngine(ctx, a, ans, b)
+	require.NoError(t, err)
+
+	it := s.Search(ctx, search.Request{Query: "6*7"})
+	defer it.Close()
+	var got []string
+	for it.Next(ctx) {
+		got = append(got, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 4)
+	require.Equal(t, "42", got[0])
+	require.ElementsMatch(t, []string{"a1.com", "a2.com", "b1.com"}, got[1:])
+}
+
+func TestPublicEngine(t *testing.T) {
+	ctx := context.Background()
+	providers.Register("test-public", func(ctx context.Context) (providers.Provider, error) {
+		return &fakeService{id: "public"}, nil
+	})
+	providers.RegisterPrivate("test-private", func(ctx context.Context) (providers.Provider, error) {
+		return &fakeService{id: "private"}, nil
+	})
+
+	s, err := NewPublicEngine(ctx)
+	require.NoError(t, err)
+	require.Len(t, s.provs, 1)
+	require.Equal(t, "public", s.provs[0].ID())
+
+	s, err = NewEngine(ctx)
+	require.NoError(t, err)
+	require.Len(t, s.provs, 2)
+}
@@ -1,12 +1,19 @@
 module github.com/dennwc/metasearch
 
+go 1.18
+
 require (
 	github.com/PuerkitoBio/goquery v1.5.0
+	github.com/spf13/cobra v0.0.3
+	github.com/stretchr/testify v1.2.2
+	golang.org/x/text v0.3.0
+)
+
+require (
+	github.com/andybalholm/cascadia v1.0.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/spf13/cobra v0.0.3
 	github.com/spf13/pflag v1.0.3 // indirect
-	github.com/stretchr/testify v1.2.2
-	golang.org/x/text v0.3.0
+	golang.org/x/net v0.0.0-20181114220301-adae6a3d119a // indirect
 )
@@ -0,0 +1,108 @@
+package metasearch
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+// locale is a cached list of languages and regions supported by a search provider.
+type locale struct {
+	langs   []search.Language
+	regions []search.Region
+	matcher language.Matcher // nil if the provider accepts any language
+}
+
+func newLocale(ctx context.Context, p search.Service) *locale {
+	l := &locale{}
+	langs, err := p.Languages(ctx)
+	if err != nil {
+		log.Printf("%s: cannot list languages: %v", p.ID(), err)
+	} else if len(langs) != 0 {
+		l.langs = langs
+		tags := make([]language.Tag, 0, len(langs))
+		for _, v := range langs {
+			tags = append(tags, v.Code)
+		}
+		l.matcher = language.NewMatcher(tags)
+	}
+	regions, err := p.Regions(ctx)
+	if err != nil {
+		log.Printf("%s: cannot list regions: %v", p.ID(), err)
+	} else {
+		l.regions = regions
+	}
+	return l
+}
+
+// loadLocales requests supported languages and regions from all search providers concurrently.
+func loadLocales(ctx context.Context, provs []search.Service) map[string]*locale {
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		out = make(map[string]*locale, len(provs))
+	)
+	for _, p := range provs {
+		wg.Add(1)
+		go func(p search.Service) {
+			defer wg.Done()
+			l := newLocale(ctx, p)
+			mu.Lock()
+			out[p.ID()] = l
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return out
+}
+
+// matchLang returns the closest language supported by the provider.
+// It returns false if none of the supported languages is an acceptable match.
+func (l *locale) matchLang(lang search.LangCode) (search.LangCode, bool) {
+	if l == nil || l.matcher == nil || lang.IsRoot() {
+		return lang, true
+	}
+	_, i, conf := l.matcher.Match(lang)
+	if conf == language.No || i < 0 || i >= len(l.langs) {
+		return search.LangCode{}, false
+	}
+	return l.langs[i].Code, true
+}
+
+// hasRegion checks if the region is supported by the provider.
+func (l *locale) hasRegion(r search.RegionCode) bool {
+	if l == nil || len(l.regions) == 0 {
+		return true
+	}
+	for _, v := range l.regions {
+		if v.Code == r {
+			return true
+		}
+	}
+	return false
+}
+
+// localize converts the request to use the language and region supported by the provider.
+// It returns false if the provider cannot serve the requested language.
+func (l *locale) localize(req search.Request) (search.Request, bool) {
+	if req.Region == (search.RegionCode{}) {
+		// keep the region of the requested language, since the provider may only support the base language
+		if r, conf := req.Lang.Region(); conf == language.Exact {
+			req.Region = r
+		}
+	}
+	lang, ok := l.matchLang(req.Lang)
+	if !ok {
+		return req, false
+	}
+	req.Lang = lang
+	if req.Region != (search.RegionCode{}) && !l.hasRegion(req.Region) {
+		// fallback to the default region of the provider
+		req.Region = search.RegionCode{}
+	}
+	return req, true
+}
@@ -1,7 +1,30 @@
 package all
 
 import (
+	_ "github.com/dennwc/metasearch/providers/archive"
+	_ "github.com/dennwc/metasearch/providers/arxiv"
+	_ "github.com/dennwc/metasearch/providers/bing"
+	_ "github.com/dennwc/metasearch/providers/bookmarks"
+	_ "github.com/dennwc/metasearch/providers/brave"
+	_ "github.com/dennwc/metasearch/providers/crates"
 	_ "github.com/dennwc/metasearch/providers/duckduckgo"
+	_ "github.com/dennwc/metasearch/providers/github"
+	_ "github.com/dennwc/metasearch/providers/godoc"
 	_ "github.com/dennwc/metasearch/providers/google"
+	_ "github.com/dennwc/metasearch/providers/hackernews"
+	_ "github.com/dennwc/metasearch/providers/instant"
+	_ "github.com/dennwc/metasearch/providers/local"
+	_ "github.com/dennwc/metasearch/providers/mojeek"
+	_ "github.com/dennwc/metasearch/providers/nominatim"
+	_ "github.com/dennwc/metasearch/providers/npm"
+	_ "github.com/dennwc/metasearch/providers/opensearch"
+	_ "github.com/dennwc/metasearch/providers/plugin"
+	_ "github.com/dennwc/metasearch/providers/pypi"
+	_ "github.com/dennwc/metasearch/providers/reddit"
+	_ "github.com/dennwc/metasearch/providers/remote"
+	_ "github.com/dennwc/metasearch/providers/scraper"
+	_ "github.com/dennwc/metasearch/providers/searx"
+	_ "github.com/dennwc/metasearch/providers/stackexchange"
+	_ "github.com/dennwc/metasearch/providers/wikidata"
 	_ "github.com/dennwc/metasearch/providers/wikipedia"
 )
@@ -0,0 +1,271 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "archive"
+	baseURL    = "https://archive.org"
+	searchPath = "/advancedsearch.php"
+	itemURL    = baseURL + "/details/"
+)
+
+var (
+	DefaultRows = 20
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var (
+	_ search.Service        = (*Service)(nil)
+	_ search.SnapshotFinder = (*Service)(nil)
+)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+		Wayback:    NewWayback(),
+	}
+}
+
+// Service searches items on the Internet Archive and finds archived copies of web pages.
+type Service struct {
+	providers.HTTPClient
+
+	Wayback *Wayback
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// Snapshot implements search.SnapshotFinder.
+func (s *Service) Snapshot(ctx context.Context, u *url.URL, at time.Time) (*search.Snapshot, error) {
+	return s.Wayback.Snapshot(ctx, u, at)
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, Page: 1}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Response.NumFound, fetched: true, page: resp.Response.Docs, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []Doc
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Page*DefaultRows >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Response.Docs
+	it.total = resp.Response.NumFound
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page"`
+}
+
+// strList is a metadata field that may be either a single string or a list of strings.
+type strList []string
+
+func (l *strList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = strList{s}
+		return nil
+	}
+	var arr []string
+	if err := json.Unmarshal(data, &arr); err != nil {
+		return err
+	}
+	*l = arr
+	return nil
+}
+
+// Doc is a single item returned by the advanced search.
+type Doc struct {
+	Identifier  string  `json:"identifier"`
+	Title       strList `json:"title"`
+	Description strList `json:"description"`
+	MediaType   string  `json:"mediatype"`
+	Creator     strList `json:"creator"`
+	Date        string  `json:"date"`
+	Downloads   int     `json:"downloads"`
+}
+
+// htmlText converts an item description, which may contain HTML markup, to plain text.
+func htmlText(s string) string {
+	if !strings.Contains(s, "<") {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	// keep paragraphs and lines separated once the markup is removed
+	s = strings.NewReplacer("<p>", " <p>", "<br", " <br").Replace(s)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+func (d *Doc) toResult() (*search.ArchiveItemResult, error) {
+	u, err := url.Parse(itemURL + url.PathEscape(d.Identifier))
+	if err != nil {
+		return nil, err
+	}
+	r := &search.ArchiveItemResult{
+		LinkResult: search.LinkResult{URL: *u, Title: strings.Join(d.Title, " "), Desc: htmlText(strings.Join(d.Description, " "))},
+		Identifier: d.Identifier,
+		MediaType:  d.MediaType,
+		Creators:   []string(d.Creator),
+		Downloads:  d.Downloads,
+	}
+	if r.Title == "" {
+		r.Title = d.Identifier
+	}
+	if d.Date != "" {
+		// dates are not validated by the archive, thus malformed ones are ignored
+		r.Date, _ = time.Parse(time.RFC3339, d.Date)
+	}
+	return r, nil
+}
+
+type SearchResp struct {
+	Response struct {
+		NumFound int   `json:"numFound"`
+		Start    int   `json:"start"`
+		Docs     []Doc `json:"docs"`
+	} `json:"response"`
+}
+
+var searchFields = []string{"identifier", "title", "description", "mediatype", "creator", "date", "downloads"}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params["fl[]"] = searchFields
+	params.Set("rows", strconv.Itoa(DefaultRows))
+	params.Set("page", strconv.Itoa(r.Page))
+	params.Set("output", "json")
+	var out SearchResp
+	if err := s.GetJSON(ctx, searchPath, params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	defer func(n int) { DefaultRows = n }(DefaultRows)
+	DefaultRows = 2
+
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/search_1.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/search_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "archive.org", r.Host)
+		require.Equal(t, searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "1" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "nasa apollo"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, searchFields, reqs[0]["fl[]"])
+	require.Equal(t, "2", reqs[1].Get("page"))
+
+	require.Len(t, got, 3)
+	require.Equal(t, &search.ArchiveItemResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://archive.org/details/Apollo11Audio"),
+			Title: "Apollo 11 Mission Audio",
+			Desc:  "Apollo 11 mission audio. Recorded in Houston.",
+		},
+		Identifier: "Apollo11Audio",
+		MediaType:  "audio",
+		Creators:   []string{"NASA", "Johnson Space Center"},
+		Date:       time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC),
+		Downloads:  48213,
+	}, got[0])
+
+	kit := got[1].(*search.ArchiveItemResult)
+	require.Equal(t, "Press kit for Apollo 13", kit.Desc)
+	require.True(t, kit.Date.IsZero())
+	require.Equal(t, "nasa_apollo_photos", got[2].GetTitle())
+}
+
+func TestParseCDX(t *testing.T) {
+	data, err := os.ReadFile("testdata/cdx.json")
+	require.NoError(t, err)
+	var rows [][]string
+	require.NoError(t, json.Unmarshal(data, &rows))
+	got, err := parseCDX(rows)
+	require.NoError(t, err)
+	require.Equal(t, []Capture{
+		{Time: time.Date(2002, 1, 20, 14, 25, 10, 0, time.UTC), Original: "http://example.com:80/", Status: 200, MIME: "text/html"},
+		{Time: time.Date(2002, 3, 28, 1, 28, 21, 0, time.UTC), Original: "http://www.example.com:80/", Status: 302, MIME: "text/html"},
+		{Time: time.Date(2002, 5, 24, 0, 22, 13, 0, time.UTC), Original: "http://www.example.com:80/", MIME: "warc/revisit"},
+	}, got)
+	require.Equal(t, "https://web.archive.org/web/20020120142510/http://example.com:80/", got[0].SnapshotURL())
+
+	got, err = parseCDX(nil)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestSnapshot(t *testing.T) {
+	var cdx []url.Values
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page := q.Get("url")
+		switch r.Host + r.URL.Path {
+		case "archive.org/wayback/available":
+			file := "testdata/available_empty.json"
+			if page == "http://example.com/" {
+				file = "testdata/available.json"
+			}
+			providertest.ServeFile(t, file, "application/json")(w, r)
+		case "web.archive.org" + cdxPath:
+			cdx = append(cdx, q)
+			file := "testdata/cdx_empty.json"
+			if page == "http://www.example.com/" {
+				file = "testdata/cdx_closest.json"
+			}
+			providertest.ServeFile(t, file, "application/json")(w, r)
+		default:
+			t.Errorf("unexpected request: %v", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	s := New()
+	s.Wayback.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	at := time.Date(2002, 4, 1, 0, 0, 0, 0, time.UTC)
+	snap, err := s.Snapshot(ctx, providertest.MustURL("http://example.com/"), at)
+	require.NoError(t, err)
+	require.Equal(t, &search.Snapshot{
+		URL:      providertest.MustURL("https://web.archive.org/web/20130919044612/http://example.com/"),
+		Original: providertest.MustURL("http://example.com/"),
+		Time:     time.Date(2013, 9, 19, 4, 46, 12, 0, time.UTC),
+		Status:   200,
+	}, snap)
+	require.Empty(t, cdx)
+
+	// not found by the availability API, falls back to CDX
+	snap, err = s.Snapshot(ctx, providertest.MustURL("http://www.example.com/"), at)
+	require.NoError(t, err)
+	require.Equal(t, "https://web.archive.org/web/20020328012821/http://www.example.com:80/", snap.URL.String())
+	require.Equal(t, 302, snap.Status)
+	require.Len(t, cdx, 1)
+	require.Equal(t, "20020401000000", cdx[0].Get("closest"))
+	require.Equal(t, "1", cdx[0].Get("limit"))
+
+	snap, err = s.Snapshot(ctx, providertest.MustURL("http://example.com/never"), time.Time{})
+	require.NoError(t, err)
+	require.Nil(t, snap)
+	require.Equal(t, "-1", cdx[1].Get("limit"))
+}
@@ -0,0 +1,194 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	waybackURL   = "https://web.archive.org"
+	availableURL = "https://archive.org/wayback/available"
+	cdxPath      = "/cdx/search/cdx"
+
+	// timeFormat is a format of Wayback Machine timestamps.
+	timeFormat = "20060102150405"
+)
+
+// NewWayback creates a client for the Wayback Machine.
+func NewWayback() *Wayback {
+	return &Wayback{
+		HTTPClient: providers.NewHTTPClient(waybackURL),
+	}
+}
+
+// Wayback looks up archived copies of web pages in the Wayback Machine.
+type Wayback struct {
+	providers.HTTPClient
+}
+
+// Capture is a single entry of the CDX index.
+type Capture struct {
+	Time     time.Time
+	Original string
+	Status   int
+	MIME     string
+}
+
+// SnapshotURL returns a link to the archived copy.
+func (c *Capture) SnapshotURL() string {
+	return waybackURL + "/web/" + c.Time.Format(timeFormat) + "/" + c.Original
+}
+
+func (c *Capture) toSnapshot() (*search.Snapshot, error) {
+	u, err := url.Parse(c.SnapshotURL())
+	if err != nil {
+		return nil, err
+	}
+	orig, err := url.Parse(c.Original)
+	if err != nil {
+		return nil, err
+	}
+	return &search.Snapshot{URL: u, Original: orig, Time: c.Time, Status: c.Status}, nil
+}
+
+// CDXReq is a query to the CDX index.
+type CDXReq struct {
+	URL string
+	// Closest sorts captures by the distance to a given time.
+	Closest time.Time
+	From    time.Time
+	To      time.Time
+	// Limit the number of captures. Negative values return the last captures.
+	Limit int
+	// OK only returns successful captures and redirects.
+	OK bool
+}
+
+// Captures lists captures of a given URL from the CDX index.
+func (w *Wayback) Captures(ctx context.Context, r CDXReq) ([]Capture, error) {
+	params := make(url.Values)
+	params.Set("url", r.URL)
+	params.Set("output", "json")
+	params.Set("fl", "timestamp,original,statuscode,mimetype")
+	if !r.Closest.IsZero() {
+		params.Set("sort", "closest")
+		params.Set("closest", r.Closest.UTC().Format(timeFormat))
+	}
+	if !r.From.IsZero() {
+		params.Set("from", r.From.UTC().Format(timeFormat))
+	}
+	if !r.To.IsZero() {
+		params.Set("to", r.To.UTC().Format(timeFormat))
+	}
+	if r.Limit != 0 {
+		params.Set("limit", strconv.Itoa(r.Limit))
+	}
+	if r.OK {
+		params.Set("filter", "statuscode:[23]..")
+	}
+	var rows [][]string
+	if err := w.GetJSON(ctx, cdxPath, params, &rows); err != nil {
+		return nil, err
+	}
+	return parseCDX(rows)
+}
+
+// parseCDX converts CDX rows to captures. The first row is a header with field names.
+func parseCDX(rows [][]string) ([]Capture, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	cols := make(map[string]int)
+	for i, name := range rows[0] {
+		cols[name] = i
+	}
+	get := func(row []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+	out := make([]Capture, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		t, err := time.Parse(timeFormat, get(row, "timestamp"))
+		if err != nil {
+			return nil, fmt.Errorf("cdx: %v", err)
+		}
+		c := Capture{Time: t, Original: get(row, "original"), MIME: get(row, "mimetype")}
+		if s := get(row, "statuscode"); s != "" && s != "-" {
+			if c.Status, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("cdx: %v", err)
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+type availableResp struct {
+	Snapshots struct {
+		Closest *struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+			Timestamp string `json:"timestamp"`
+			Status    string `json:"status"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+// Available finds the capture closest to a given time using the availability API.
+// If the time is zero, the latest capture is returned. It returns nil if the page was never archived.
+func (w *Wayback) Available(ctx context.Context, u *url.URL, at time.Time) (*Capture, error) {
+	params := make(url.Values)
+	params.Set("url", u.String())
+	if !at.IsZero() {
+		params.Set("timestamp", at.UTC().Format(timeFormat))
+	}
+	req, err := http.NewRequest("GET", availableURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var out availableResp
+	if _, err := w.DoJSON(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	c := out.Snapshots.Closest
+	if c == nil || !c.Available {
+		return nil, nil
+	}
+	t, err := time.Parse(timeFormat, c.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	st, _ := strconv.Atoi(c.Status)
+	return &Capture{Time: t, Original: u.String(), Status: st}, nil
+}
+
+// Snapshot finds an archived copy of the page closest to a given time, or the latest one if the time is zero.
+func (w *Wayback) Snapshot(ctx context.Context, u *url.URL, at time.Time) (*search.Snapshot, error) {
+	c, err := w.Available(ctx, u, at)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		// the availability API is known to miss some captures, while the CDX index is authoritative
+		r := CDXReq{URL: u.String(), Closest: at, Limit: 1, OK: true}
+		if at.IsZero() {
+			r.Limit = -1
+		}
+		arr, err := w.Captures(ctx, r)
+		if err != nil || len(arr) == 0 {
+			return nil, err
+		}
+		c = &arr[0]
+	}
+	return c.toSnapshot()
+}
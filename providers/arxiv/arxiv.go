@@ -0,0 +1,342 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName  = "arxiv"
+	baseURL   = "https://export.arxiv.org"
+	queryPath = "/api/query"
+
+	errorsID = "http://arxiv.org/api/errors"
+)
+
+var (
+	DefaultMaxResults = 20
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// reField matches arXiv field prefixes, such as "au:" or "ti:".
+var reField = regexp.MustCompile(`(^|[\s(])(ti|au|abs|co|jr|cat|rn|id|all):`)
+
+// isOperator checks if the word is one of arXiv boolean operators.
+func isOperator(w string) bool {
+	switch w {
+	case "AND", "OR", "ANDNOT":
+		return true
+	}
+	return false
+}
+
+// toQuery converts a search query to the arXiv query syntax.
+// Queries that already use field prefixes are passed as-is. Otherwise, words and quoted phrases
+// are searched in all fields and joined with AND, unless an operator or parentheses are used.
+func toQuery(q string) string {
+	q = strings.TrimSpace(q)
+	if reField.MatchString(q) {
+		return q
+	}
+	var (
+		b       strings.Builder
+		operand bool // the last token ends an operand
+	)
+	add := func(tok string) {
+		if b.Len() != 0 && !strings.HasSuffix(b.String(), "(") && tok != ")" {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok)
+	}
+	term := func(t string) {
+		if operand {
+			add("AND")
+		}
+		add("all:" + t)
+		operand = true
+	}
+	for i := 0; i < len(q); {
+		switch c := q[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			if operand {
+				add("AND")
+			}
+			add("(")
+			operand = false
+			i++
+		case c == ')':
+			add(")")
+			operand = true
+			i++
+		case c == '"':
+			j := strings.IndexByte(q[i+1:], '"')
+			if j < 0 {
+				j = len(q) - i - 1
+			}
+			if phrase := strings.TrimSpace(q[i+1 : i+1+j]); phrase != "" {
+				term(`"` + phrase + `"`)
+			}
+			i += j + 2
+		default:
+			j := strings.IndexAny(q[i:], " \t\n()\"")
+			if j < 0 {
+				j = len(q) - i
+			}
+			if w := q[i : i+j]; isOperator(w) {
+				add(w)
+				operand = false
+			} else {
+				term(w)
+			}
+			i += j
+		}
+	}
+	return b.String()
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query: toQuery(req.Query),
+		Limit: DefaultMaxResults,
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Total, fetched: true, page: resp.Entries, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []Entry
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Start+len(it.page) >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.Start += len(it.page)
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Entries
+	it.total = resp.Total
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Start int    `json:"start"`
+	Limit int    `json:"limit"`
+}
+
+type Link struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+	Type  string `xml:"type,attr"`
+	Title string `xml:"title,attr"`
+}
+
+// Entry is a single paper in the arXiv Atom feed.
+type Entry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Summary   string    `xml:"summary"`
+	Published time.Time `xml:"published"`
+	Updated   time.Time `xml:"updated"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Links      []Link `xml:"link"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// clean collapses whitespace; titles and abstracts in the feed are wrapped.
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (e *Entry) toResult() (*search.PaperResult, error) {
+	addr := e.ID
+	var pdf string
+	for _, l := range e.Links {
+		switch {
+		case l.Rel == "alternate" && (l.Type == "" || l.Type == "text/html"):
+			addr = l.Href
+		case l.Title == "pdf" || l.Type == "application/pdf":
+			pdf = l.Href
+		}
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	r := &search.PaperResult{
+		LinkResult: search.LinkResult{URL: *u, Title: clean(e.Title), Desc: clean(e.Summary)},
+		Published:  e.Published,
+		Updated:    e.Updated,
+	}
+	if pdf != "" {
+		if r.PDF, err = url.Parse(pdf); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range e.Authors {
+		r.Authors = append(r.Authors, a.Name)
+	}
+	for _, c := range e.Categories {
+		r.Categories = append(r.Categories, c.Term)
+	}
+	return r, nil
+}
+
+type SearchResp struct {
+	Total        int     `xml:"totalResults"`
+	StartIndex   int     `xml:"startIndex"`
+	ItemsPerPage int     `xml:"itemsPerPage"`
+	Entries      []Entry `xml:"entry"`
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("search_query", r.Query)
+	params.Set("start", strconv.Itoa(r.Start))
+	if r.Limit > 0 {
+		params.Set("max_results", strconv.Itoa(r.Limit))
+	}
+	var out SearchResp
+	if err := s.GetXML(ctx, queryPath, params, &out); err != nil {
+		return nil, err
+	}
+	// errors are reported as a single entry in the feed
+	if len(out.Entries) == 1 && strings.HasPrefix(out.Entries[0].ID, errorsID) {
+		return nil, fmt.Errorf("arxiv: %s", clean(out.Entries[0].Summary))
+	}
+	return &out, nil
+}
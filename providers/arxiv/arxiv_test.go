@@ -0,0 +1,90 @@
+package arxiv
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToQuery(t *testing.T) {
+	for _, c := range []struct {
+		in, out string
+	}{
+		{"electron", "all:electron"},
+		{" quantum  checklist ", "all:quantum AND all:checklist"},
+		{"au:del_maestro AND ti:checklist", "au:del_maestro AND ti:checklist"},
+		{"(cat:hep-th OR cat:hep-ph) ANDNOT electron", "(cat:hep-th OR cat:hep-ph) ANDNOT electron"},
+		{"http://example.com", "all:http://example.com"},
+		{"quantum OR photon", "all:quantum OR all:photon"},
+		{"electron ANDNOT proton", "all:electron ANDNOT all:proton"},
+		{"this or that", "all:this AND all:or AND all:that"},
+		{`"dark matter" halo`, `all:"dark matter" AND all:halo`},
+		{`halo "dark matter`, `all:halo AND all:"dark matter"`},
+		{"(quantum OR photon) laser", "(all:quantum OR all:photon) AND all:laser"},
+	} {
+		require.Equal(t, c.out, toQuery(c.in), c.in)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page0 := providertest.ServeFile(t, "testdata/query_0.xml", "application/atom+xml")
+	page2 := providertest.ServeFile(t, "testdata/query_2.xml", "application/atom+xml")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "export.arxiv.org", r.Host)
+		require.Equal(t, queryPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("start") == "0" {
+			page0(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "au:del_maestro AND ti:checklist"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []url.Values{
+		{"search_query": {"au:del_maestro AND ti:checklist"}, "start": {"0"}, "max_results": {"20"}},
+		{"search_query": {"au:del_maestro AND ti:checklist"}, "start": {"2"}, "max_results": {"20"}},
+	}, reqs)
+	require.Len(t, got, 3)
+	require.Equal(t, &search.PaperResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("http://arxiv.org/abs/2208.05413v1"),
+			Title: "A checklist for quantum simulations",
+			Desc:  "We provide a checklist of best practices for simulations of quantum many-body systems.",
+		},
+		Authors:    []string{"Adrian Del Maestro", "Jane Doe"},
+		Categories: []string{"cond-mat.stat-mech", "physics.comp-ph"},
+		Published:  time.Date(2022, 8, 10, 15, 55, 45, 0, time.UTC),
+		Updated:    time.Date(2022, 8, 10, 15, 55, 45, 0, time.UTC),
+		PDF:        providertest.MustURL("http://arxiv.org/pdf/2208.05413v1"),
+	}, got[0])
+	last := got[2].(*search.PaperResult)
+	require.Equal(t, "Old checklist", last.Title)
+	require.Nil(t, last.PDF)
+}
+
+func TestSearchError(t *testing.T) {
+	_, cli := providertest.NewServer(t, providertest.ServeFile(t, "testdata/error.xml", "application/atom+xml"))
+	s := New()
+	s.SetHTTPClient(cli)
+
+	_, err := s.SearchRaw(context.Background(), SearchReq{Query: "all:electron", Start: -1})
+	require.EqualError(t, err, "arxiv: start must be non-negative")
+}
@@ -0,0 +1,254 @@
+package bing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+var _ search.Service = (*Service)(nil)
+
+const (
+	provName   = "bing"
+	perPage    = 10
+	baseURL    = "https://www.bing.com"
+	searchPath = "/search"
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query:      req.Query,
+		Offset:     0,
+		Market:     toMarket(req.Lang, req.Region),
+		SafeSearch: req.Safe,
+	}
+	if !req.Lang.IsRoot() {
+		base, _ := req.Lang.Base()
+		r.Language = base.String()
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s   *Service
+	cur SearchReq
+
+	page []Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	it.cur.Offset += len(it.page)
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Results
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return &search.LinkResult{
+		URL: *u, Title: r.Title, Desc: r.Content,
+	}
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query      string `json:"q"`
+	Offset     int    `json:"off"`
+	Market     string `json:"mkt"`
+	Language   string `json:"lang"`
+	SafeSearch bool   `json:"safe"`
+}
+
+type Result struct {
+	Title   string
+	URL     string
+	Content string
+}
+
+type SearchResp struct {
+	Total   uint
+	Results []Result
+}
+
+var reTotal = regexp.MustCompile(`([\d,.]+)`)
+
+// unwrapURL extracts the target URL from Bing click-tracking links.
+func unwrapURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || !strings.HasSuffix(u.Host, "bing.com") || !strings.HasPrefix(u.Path, "/ck/") {
+		return link
+	}
+	v := u.Query().Get("u")
+	if !strings.HasPrefix(v, "a1") {
+		return link
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(v[2:], "="))
+	if err != nil || !strings.HasPrefix(string(data), "http") {
+		return link
+	}
+	return string(data)
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	if r.Market == "" {
+		r.Market = defaultMarket
+	}
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("first", strconv.Itoa(r.Offset+1))
+	params.Set("count", strconv.Itoa(perPage))
+	params.Set("mkt", r.Market)
+	if r.Language != "" {
+		params.Set("setlang", r.Language)
+	}
+	if r.SafeSearch {
+		params.Set("adlt", "strict")
+	} else {
+		params.Set("adlt", "off")
+	}
+	req, err := s.GetRequest(searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	adult := "OFF"
+	if r.SafeSearch {
+		adult = "STRICT"
+	}
+	req.AddCookie(&http.Cookie{Name: "SRCHHPGUSR", Value: "ADLT=" + adult})
+	doc, err := s.DoHTML(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	out := &SearchResp{}
+	doc.Find(`.sb_count`).Each(func(_ int, sel *goquery.Selection) {
+		s := reTotal.FindString(sel.Text()) // About 1,230,000 results
+		s = strings.NewReplacer(",", "", ".", "").Replace(s)
+		if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			out.Total = uint(v)
+		}
+	})
+	doc.Find(`#b_results > li.b_algo`).Each(func(_ int, sel *goquery.Selection) {
+		a := sel.Find(`h2 a`).First()
+		link := a.AttrOr("href", "")
+		if !strings.HasPrefix(link, "http") {
+			return
+		}
+		content := sel.Find(`.b_caption p`).First()
+		if content.Size() == 0 {
+			content = sel.Find(`p`).First()
+		}
+		out.Results = append(out.Results, Result{
+			Title:   strings.TrimSpace(a.Text()),
+			URL:     unwrapURL(link),
+			Content: strings.TrimSpace(content.Text()),
+		})
+	})
+	return out, nil
+}
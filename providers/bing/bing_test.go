@@ -0,0 +1,138 @@
+package bing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRaw(t *testing.T) {
+	var last *http.Request
+	page := providertest.ServeFile(t, "testdata/solar.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		page(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	resp, err := s.SearchRaw(ctx, SearchReq{
+		Query: "solar", Offset: 10, Market: "de-DE", Language: "de", SafeSearch: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &SearchResp{
+		Total: 1230000000,
+		Results: []Result{
+			{
+				Title:   "Solar Energy Technologies Office | Department of Energy",
+				URL:     "https://www.energy.gov/solar",
+				Content: "The Solar Energy Technologies Office supports research and development.",
+			},
+			{
+				Title:   "Solar energy - Wikipedia",
+				URL:     "https://en.wikipedia.org/wiki/Solar_energy",
+				Content: "Solar energy is radiant light and heat from the Sun.",
+			},
+			{
+				Title:   "The Sun - NASA",
+				URL:     "https://www.nasa.gov/sun",
+				Content: "Our star.",
+			},
+		},
+	}, resp)
+
+	require.Equal(t, "www.bing.com", last.Host)
+	require.Equal(t, searchPath, last.URL.Path)
+	q := last.URL.Query()
+	require.Equal(t, "solar", q.Get("q"))
+	require.Equal(t, "11", q.Get("first"))
+	require.Equal(t, "de-DE", q.Get("mkt"))
+	require.Equal(t, "de", q.Get("setlang"))
+	require.Equal(t, "strict", q.Get("adlt"))
+	c, err := last.Cookie("SRCHHPGUSR")
+	require.NoError(t, err)
+	require.Equal(t, "ADLT=STRICT", c.Value)
+}
+
+func TestSearch(t *testing.T) {
+	var offsets []string
+	page := providertest.ServeFile(t, "testdata/solar.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first := r.URL.Query().Get("first")
+		offsets = append(offsets, first)
+		if first == "7" {
+			w.Write([]byte(`<html><body><ol id="b_results"></ol></body></html>`))
+			return
+		}
+		page(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "solar"})
+	defer it.Close()
+	var (
+		got []string
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		got = append(got, it.Result().GetURL().String())
+		if len(got) == 4 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 6)
+	require.Equal(t, []string{"1", "4", "7"}, offsets)
+
+	offsets = nil
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	got = nil
+	for it.Next(ctx) {
+		got = append(got, it.Result().GetURL().String())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{
+		"https://en.wikipedia.org/wiki/Solar_energy",
+		"https://www.nasa.gov/sun",
+	}, got)
+	require.Equal(t, []string{"4", "7"}, offsets)
+}
+
+func TestToMarket(t *testing.T) {
+	for _, c := range []struct {
+		lang   string
+		region string
+		exp    string
+	}{
+		{exp: "en-US"},
+		{lang: "de", exp: "de-DE"},
+		{lang: "de-AT", exp: "de-AT"},
+		{lang: "fr", region: "CH", exp: "fr-CH"},
+		{region: "BE", exp: "nl-BE"},
+		{lang: "no", exp: "nb-NO"},
+		{lang: "is", exp: "en-US"},
+		{lang: "ja", region: "KR", exp: "ja-JP"},
+	} {
+		t.Run(c.lang+"-"+c.region, func(t *testing.T) {
+			var (
+				lang search.LangCode
+				reg  search.RegionCode
+			)
+			if c.lang != "" {
+				lang = search.MustParseLangCode(c.lang)
+			}
+			if c.region != "" {
+				reg = search.MustParseRegionCode(c.region)
+			}
+			require.Equal(t, c.exp, toMarket(lang, reg))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package bing
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+const defaultMarket = "en-US"
+
+// markets is a list of markets supported by Bing. The default market goes first,
+// since it's preferred by the matcher.
+var markets = []string{
+	defaultMarket, "es-US", "en-GB", "en-AU", "en-CA", "en-IN", "en-ID", "en-MY",
+	"en-NZ", "en-PH", "en-ZA", "es-AR", "de-AT", "nl-BE", "fr-BE", "pt-BR",
+	"fr-CA", "es-CL", "da-DK", "fi-FI", "fr-FR", "de-DE", "zh-HK", "it-IT",
+	"ja-JP", "ko-KR", "es-MX", "nl-NL", "nb-NO", "zh-CN", "pl-PL", "ru-RU",
+	"es-ES", "sv-SE", "fr-CH", "de-CH", "zh-TW", "tr-TR",
+}
+
+var (
+	marketTags    []language.Tag
+	marketMatcher language.Matcher
+)
+
+func init() {
+	marketTags = make([]language.Tag, 0, len(markets))
+	for _, m := range markets {
+		marketTags = append(marketTags, language.MustParse(m))
+	}
+	marketMatcher = language.NewMatcher(marketTags)
+}
+
+// toMarket selects the closest Bing market for a given language and region.
+func toMarket(lang search.LangCode, reg search.RegionCode) string {
+	if lang.IsRoot() && reg == (search.RegionCode{}) {
+		return defaultMarket
+	}
+	if reg != (search.RegionCode{}) {
+		if lang.IsRoot() {
+			// pick the first market for the country
+			for i, t := range marketTags {
+				if r, _ := t.Region(); r == reg {
+					return markets[i]
+				}
+			}
+			return defaultMarket
+		}
+		if t, err := language.Compose(lang, reg); err == nil {
+			lang = t
+		}
+	}
+	_, i, conf := marketMatcher.Match(lang)
+	if conf < language.High || i < 0 || i >= len(markets) {
+		return defaultMarket
+	}
+	return markets[i]
+}
+
+// Languages returns a list of Bing markets.
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	names := display.English.Tags()
+	out := make([]search.Language, 0, len(marketTags))
+	for _, t := range marketTags {
+		out = append(out, search.Language{Code: t, Name: names.Name(t)})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	names := display.English.Regions()
+	seen := make(map[search.RegionCode]struct{})
+	var out []search.Region
+	for _, t := range marketTags {
+		r, _ := t.Region()
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		out = append(out, search.Region{Code: r, Name: names.Name(r)})
+	}
+	return out, nil
+}
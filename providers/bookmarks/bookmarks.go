@@ -0,0 +1,441 @@
+package bookmarks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "bookmarks"
+
+	// EnvFiles is a list of bookmark exports (HTML) and browser history databases
+	// (places.sqlite for Firefox, History for Chromium).
+	EnvFiles = "METAS_BOOKMARKS"
+)
+
+var (
+	DefaultPageSize    = 20
+	DefaultSuggestions = 10
+	// ReloadInterval is a minimal interval between checks for changed files.
+	ReloadInterval = time.Minute
+)
+
+func init() {
+	providers.RegisterPrivate(provName, func(ctx context.Context) (providers.Provider, error) {
+		files := providers.EnvList(EnvFiles)
+		if len(files) == 0 {
+			return nil, nil
+		}
+		return New(files...), nil
+	})
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// Entry is a bookmark or a page from the browser history.
+type Entry struct {
+	URL        string
+	Title      string
+	Desc       string
+	Visits     int
+	LastVisit  time.Time
+	Bookmarked bool
+	Folder     string
+	Tags       []string
+
+	text string // lower-cased title, URL, folder and tags used for matching
+}
+
+// score ranks entries similar to browser address bars: bookmarks and frequently visited pages go first.
+func (e *Entry) score() float64 {
+	s := math.Log1p(float64(e.Visits))
+	if e.Bookmarked {
+		s += 2
+	}
+	return s
+}
+
+// merge adds information about the same page from a different source.
+func (e *Entry) merge(e2 *Entry) {
+	e.Visits += e2.Visits
+	if e2.LastVisit.After(e.LastVisit) {
+		e.LastVisit = e2.LastVisit
+	}
+	if e2.Bookmarked && !e.Bookmarked {
+		// titles and folders of bookmarks are set by the user
+		e.Bookmarked = true
+		e.Folder = e2.Folder
+		if e2.Title != "" {
+			e.Title = e2.Title
+		}
+	}
+	if e.Title == "" {
+		e.Title = e2.Title
+	}
+	if e.Desc == "" {
+		e.Desc = e2.Desc
+	}
+	seen := make(map[string]struct{}, len(e.Tags))
+	for _, t := range e.Tags {
+		seen[t] = struct{}{}
+	}
+	for _, t := range e2.Tags {
+		if _, ok := seen[t]; !ok {
+			e.Tags = append(e.Tags, t)
+		}
+	}
+}
+
+func (e *Entry) toResult() (*search.HistoryResult, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	title := e.Title
+	if title == "" {
+		title = e.URL
+	}
+	return &search.HistoryResult{
+		LinkResult: search.LinkResult{URL: *u, Title: title, Desc: e.Desc},
+		Visits:     e.Visits,
+		LastVisit:  e.LastVisit,
+		Bookmarked: e.Bookmarked,
+		Folder:     e.Folder,
+		Tags:       e.Tags,
+	}, nil
+}
+
+// readFile reads entries from a bookmark export or a browser history database.
+func readFile(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	hdr := make([]byte, len(sqliteMagic))
+	n, _ := f.Read(hdr)
+	f.Close()
+	if isSQLite(hdr[:n]) {
+		return readHistory(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := parseNetscape(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return out, nil
+}
+
+// New creates a provider for given bookmark exports and browser history databases.
+// Files are loaded on the first search and are reloaded when they change.
+func New(files ...string) *Service {
+	return &Service{Files: files}
+}
+
+type Service struct {
+	Files []string
+
+	mu      sync.Mutex
+	checked time.Time
+	mtimes  map[string]time.Time
+	entries []*Entry
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// modTime returns the latest modification time of the file and its write-ahead log.
+func modTime(path string) (time.Time, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := fi.ModTime()
+	if fi, err := os.Stat(path + "-wal"); err == nil && fi.ModTime().After(t) {
+		t = fi.ModTime()
+	}
+	return t, nil
+}
+
+// Entries returns all bookmarks and history entries, reloading files if they have changed.
+// Entries for the same URL are merged. Entries are sorted by rank.
+func (s *Service) Entries(ctx context.Context) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mtimes != nil && time.Since(s.checked) < ReloadInterval {
+		return s.entries, nil
+	}
+	mtimes := make(map[string]time.Time, len(s.Files))
+	changed := s.mtimes == nil
+	for _, path := range s.Files {
+		t, err := modTime(path)
+		if err != nil {
+			return nil, err
+		}
+		mtimes[path] = t
+		if old, ok := s.mtimes[path]; !ok || !old.Equal(t) {
+			changed = true
+		}
+	}
+	s.checked = time.Now()
+	if !changed {
+		return s.entries, nil
+	}
+	byURL := make(map[string]*Entry)
+	var all []*Entry
+	for _, path := range s.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		arr, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range arr {
+			if e.URL == "" {
+				continue
+			}
+			if cur := byURL[e.URL]; cur != nil {
+				cur.merge(e)
+				continue
+			}
+			byURL[e.URL] = e
+			all = append(all, e)
+		}
+	}
+	for _, e := range all {
+		e.text = strings.ToLower(strings.Join(append([]string{e.Title, e.URL, e.Folder}, e.Tags...), " "))
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if sa, sb := a.score(), b.score(); sa != sb {
+			return sa > sb
+		}
+		if !a.LastVisit.Equal(b.LastVisit) {
+			return a.LastVisit.After(b.LastVisit)
+		}
+		return a.URL < b.URL
+	})
+	s.entries, s.mtimes = all, mtimes
+	return all, nil
+}
+
+// trimURL removes parts of the URL that are usually not typed.
+func trimURL(s string) string {
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	return strings.TrimPrefix(s, "www.")
+}
+
+// AutoComplete suggests URLs and titles of bookmarks and visited pages that start with a given text.
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	prefix := strings.ToLower(strings.TrimSpace(req.Text))
+	if prefix == "" {
+		return nil, nil
+	}
+	entries, err := s.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	seen := make(map[string]struct{})
+	for _, e := range entries {
+		if len(out) >= DefaultSuggestions {
+			break
+		}
+		var v string
+		if u := trimURL(e.URL); strings.HasPrefix(strings.ToLower(u), prefix) {
+			v = u
+		} else if strings.HasPrefix(strings.ToLower(e.Title), prefix) {
+			v = e.Title
+		} else {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Total, fetched: true, page: resp.Entries, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []*Entry
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Offset+len(it.page) >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.Offset += len(it.page)
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Entries
+	it.total = resp.Total
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query  string `json:"q"`
+	Offset int    `json:"off,omitempty"`
+}
+
+type SearchResp struct {
+	Entries []*Entry
+	Total   int
+}
+
+// SearchRaw returns a single page of entries that contain all words of the query
+// in the title, the URL, the folder name or tags.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	words := strings.Fields(strings.ToLower(r.Query))
+	if len(words) == 0 {
+		return &SearchResp{}, nil
+	}
+	entries, err := s.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var hits []*Entry
+loop:
+	for _, e := range entries {
+		for _, w := range words {
+			if !strings.Contains(e.text, w) {
+				continue loop
+			}
+		}
+		hits = append(hits, e)
+	}
+	resp := &SearchResp{Total: len(hits)}
+	if r.Offset >= len(hits) {
+		return resp, nil
+	}
+	hits = hits[r.Offset:]
+	if len(hits) > DefaultPageSize {
+		hits = hits[:DefaultPageSize]
+	}
+	resp.Entries = hits
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+package bookmarks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetscape(t *testing.T) {
+	data, err := os.ReadFile("testdata/bookmarks.html")
+	require.NoError(t, err)
+	got, err := parseNetscape(data)
+	require.NoError(t, err)
+	require.Equal(t, []*Entry{
+		{
+			URL: "https://go.dev/", Title: "Go", Bookmarked: true, Folder: "Bookmarks bar", Tags: []string{"go", "lang"},
+			LastVisit: time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			URL: "https://research.swtch.com/", Title: "research!rsc", Desc: "Thoughts and links about programming",
+			Bookmarked: true, Folder: "Bookmarks bar/Reading", LastVisit: time.Unix(1705000000, 0).UTC(),
+		},
+		{
+			URL: "https://news.ycombinator.com/", Title: "Hacker News", Bookmarked: true,
+			LastVisit: time.Unix(1690000000, 0).UTC(),
+		},
+	}, got)
+}
+
+func TestFirefox(t *testing.T) {
+	got, err := readFile("testdata/places.sqlite")
+	require.NoError(t, err)
+	require.Len(t, got, 4) // hidden pages are skipped
+	require.Equal(t, &Entry{
+		URL: "https://go.dev/", Title: "Go", Visits: 42, Bookmarked: true, Folder: "Go", Tags: []string{"golang"},
+		LastVisit: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, got[0])
+	require.Equal(t, "http package - net/http", got[1].Title)
+	require.Equal(t, "Go/Docs", got[1].Folder)
+	require.Equal(t, "Mozilla", got[2].Title)
+	require.Equal(t, "", got[2].Folder)
+	require.True(t, got[2].LastVisit.IsZero())
+	require.False(t, got[3].Bookmarked)
+}
+
+func TestChromium(t *testing.T) {
+	got, err := readFile("testdata/History")
+	require.NoError(t, err)
+	require.Equal(t, []*Entry{
+		{
+			URL: "https://go.dev/", Title: "The Go Programming Language", Visits: 10,
+			LastVisit: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			URL: "https://github.com/golang/go", Title: "golang/go: The Go programming language", Visits: 25,
+			LastVisit: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		},
+	}, got)
+}
+
+func TestSearch(t *testing.T) {
+	s := New("testdata/bookmarks.html", "testdata/places.sqlite", "testdata/History")
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "Go"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 3)
+	// entries from all files are merged
+	require.Equal(t, &search.HistoryResult{
+		LinkResult: search.LinkResult{URL: *providertest.MustURL("https://go.dev/"), Title: "Go"},
+		Visits:     52,
+		LastVisit:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Bookmarked: true,
+		Folder:     "Bookmarks bar",
+		Tags:       []string{"go", "lang", "golang"},
+	}, got[0])
+	require.Equal(t, "https://pkg.go.dev/net/http", got[1].GetURL().String())
+	require.Equal(t, "https://github.com/golang/go", got[2].GetURL().String())
+
+	// all words must match, including folder names
+	it = s.Search(ctx, search.Request{Query: "http docs"})
+	defer it.Close()
+	require.True(t, it.Next(ctx))
+	require.Equal(t, "http package - net/http", it.Result().GetTitle())
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+}
+
+func TestAutoComplete(t *testing.T) {
+	s := New("testdata/bookmarks.html", "testdata/History")
+	got, err := s.AutoComplete(context.Background(), autocomplete.Request{Text: "Go"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go.dev/", "golang/go: The Go programming language"}, got)
+}
+
+func TestReload(t *testing.T) {
+	defer func(d time.Duration) { ReloadInterval = d }(ReloadInterval)
+	ReloadInterval = 0
+
+	path := filepath.Join(t.TempDir(), "bookmarks.html")
+	write := func(title string, mtime time.Time) {
+		data := `<DL><p><DT><A HREF="https://example.com/">` + title + `</A></DL>`
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+	mtime := time.Now().Add(-time.Hour)
+	write("First", mtime)
+
+	s := New(path)
+	ctx := context.Background()
+	got, err := s.Entries(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "First", got[0].Title)
+
+	write("Second", mtime.Add(time.Minute))
+	got, err = s.Entries(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Second", got[0].Title)
+}
@@ -0,0 +1,156 @@
+package bookmarks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// chromeEpoch is the start of Chromium timestamps relative to the Unix epoch, in microseconds.
+// Chromium timestamps are in microseconds since 1601-01-01 UTC.
+const chromeEpoch = -11644473600 * 1000000
+
+func asInt(v interface{}) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func asString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// readHistory reads visited pages and bookmarks from a Firefox (places.sqlite) or a Chromium (History) database.
+func readHistory(path string) ([]*Entry, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := db.tables()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if t := tables["moz_places"]; t != nil {
+		return readFirefox(db, t, tables["moz_bookmarks"])
+	} else if t := tables["urls"]; t != nil {
+		return readChromium(db, t)
+	}
+	return nil, fmt.Errorf("%s: unsupported database", path)
+}
+
+func readFirefox(db *sqliteDB, places, bookmarks *sqliteTable) ([]*Entry, error) {
+	byID := make(map[int64]*Entry)
+	var out []*Entry
+	err := db.rows(places, func(row map[string]interface{}) error {
+		if asInt(row["hidden"]) != 0 {
+			return nil // redirects, embedded frames, etc
+		}
+		e := &Entry{
+			URL:    asString(row["url"]),
+			Title:  asString(row["title"]),
+			Visits: int(asInt(row["visit_count"])),
+		}
+		if ts := asInt(row["last_visit_date"]); ts != 0 {
+			e.LastVisit = time.UnixMicro(ts).UTC()
+		}
+		byID[asInt(row["id"])] = e
+		out = append(out, e)
+		return nil
+	})
+	if err != nil || bookmarks == nil {
+		return out, err
+	}
+	type folder struct {
+		parent int64
+		title  string
+		guid   string
+	}
+	folders := make(map[int64]folder)
+	type mark struct {
+		place, parent int64
+		title         string
+	}
+	var marks []mark
+	const (
+		typeBookmark = 1
+		typeFolder   = 2
+
+		tagsGUID = "tags________"
+	)
+	err = db.rows(bookmarks, func(row map[string]interface{}) error {
+		switch asInt(row["type"]) {
+		case typeBookmark:
+			marks = append(marks, mark{place: asInt(row["fk"]), parent: asInt(row["parent"]), title: asString(row["title"])})
+		case typeFolder:
+			folders[asInt(row["id"])] = folder{parent: asInt(row["parent"]), title: asString(row["title"]), guid: asString(row["guid"])}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	isFolder := func(id int64) bool {
+		_, ok := folders[id]
+		return ok
+	}
+	// folderPath returns a slash-separated path of the folder. Built-in top-level folders, such as the toolbar, are omitted.
+	folderPath := func(id int64) string {
+		var names []string
+		for i := 0; i < 64 && isFolder(id); i++ {
+			f := folders[id]
+			if !isFolder(f.parent) || !isFolder(folders[f.parent].parent) {
+				break // the root or a built-in folder
+			}
+			names = append([]string{f.title}, names...)
+			id = f.parent
+		}
+		return strings.Join(names, "/")
+	}
+	for _, m := range marks {
+		e := byID[m.place]
+		if e == nil {
+			continue
+		}
+		// tags are stored as bookmarks in child folders of the tags root
+		if f, ok := folders[m.parent]; ok && folders[f.parent].guid == tagsGUID {
+			e.Tags = append(e.Tags, f.title)
+			continue
+		}
+		e.Bookmarked = true
+		if m.title != "" {
+			e.Title = m.title
+		}
+		e.Folder = folderPath(m.parent)
+	}
+	return out, nil
+}
+
+func readChromium(db *sqliteDB, urls *sqliteTable) ([]*Entry, error) {
+	var out []*Entry
+	err := db.rows(urls, func(row map[string]interface{}) error {
+		if asInt(row["hidden"]) != 0 {
+			return nil
+		}
+		e := &Entry{
+			URL:    asString(row["url"]),
+			Title:  asString(row["title"]),
+			Visits: int(asInt(row["visit_count"])),
+		}
+		if ts := asInt(row["last_visit_time"]); ts != 0 {
+			e.LastVisit = time.UnixMicro(ts + chromeEpoch).UTC()
+		}
+		out = append(out, e)
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,65 @@
+package bookmarks
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// unixAttr parses a timestamp attribute in seconds since the Unix epoch.
+func unixAttr(s *goquery.Selection, name string) time.Time {
+	v, _ := s.Attr(name)
+	sec, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+// parseNetscape reads bookmarks exported by browsers in the Netscape bookmark file format.
+func parseNetscape(data []byte) ([]*Entry, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var out []*Entry
+	doc.Find("dt > a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		if strings.HasPrefix(href, "javascript:") || strings.HasPrefix(href, "place:") {
+			return // bookmarklets and smart folders
+		}
+		e := &Entry{
+			URL:        href,
+			Title:      strings.TrimSpace(a.Text()),
+			Bookmarked: true,
+			LastVisit:  unixAttr(a, "last_visit"),
+		}
+		if e.LastVisit.IsZero() {
+			e.LastVisit = unixAttr(a, "add_date")
+		}
+		if tags, ok := a.Attr("tags"); ok {
+			for _, t := range strings.Split(tags, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					e.Tags = append(e.Tags, t)
+				}
+			}
+		}
+		// the description is in the DD element that follows the bookmark
+		if dd := a.Parent().Next(); dd.Is("dd") {
+			e.Desc = strings.TrimSpace(dd.Text())
+		}
+		// folders are nested lists with a title in the preceding H3 element
+		var names []string
+		a.ParentsFiltered("dl").Each(func(_ int, dl *goquery.Selection) {
+			if h := dl.PrevAllFiltered("h3").First(); h.Length() != 0 {
+				names = append([]string{strings.TrimSpace(h.Text())}, names...)
+			}
+		})
+		e.Folder = strings.Join(names, "/")
+		out = append(out, e)
+	})
+	return out, nil
+}
@@ -0,0 +1,632 @@
+package bookmarks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// This file implements a minimal read-only reader for SQLite database files.
+// It only supports reading rows of tables (including WITHOUT ROWID ones), which is enough to read browser history.
+// See https://www.sqlite.org/fileformat2.html for the description of the format.
+
+const (
+	sqliteMagic      = "SQLite format 3\x00"
+	sqliteHeaderSize = 100
+
+	pageInteriorIndex = 0x02
+	pageInteriorTable = 0x05
+	pageLeafIndex     = 0x0a
+	pageLeafTable     = 0x0d
+
+	walHeaderSize      = 32
+	walFrameHeaderSize = 24
+)
+
+var errNotSQLite = errors.New("not an sqlite database")
+
+// isSQLite checks if the data starts with an SQLite file header.
+func isSQLite(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sqliteMagic))
+}
+
+type sqliteDB struct {
+	data     []byte
+	pageSize int
+	usable   int         // page size without reserved bytes
+	wal      map[int]int // page number -> offset of the latest committed page in walData
+	walData  []byte
+	walSize  int // database size in pages after the last commit in the log
+}
+
+// openSQLite reads the database file. If there is a write-ahead log next to it,
+// committed pages from the log are used as well, thus the database can be read while a browser is running.
+func openSQLite(path string) (*sqliteDB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newSQLite(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	wal, err := os.ReadFile(path + "-wal")
+	if err == nil {
+		db.readWAL(wal)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return db, nil
+}
+
+func newSQLite(data []byte) (*sqliteDB, error) {
+	if len(data) < sqliteHeaderSize || !isSQLite(data) {
+		return nil, errNotSQLite
+	}
+	db := &sqliteDB{data: data}
+	db.pageSize = int(binary.BigEndian.Uint16(data[16:]))
+	if db.pageSize == 1 {
+		db.pageSize = 65536
+	}
+	if db.pageSize < 512 || db.pageSize&(db.pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid page size: %d", db.pageSize)
+	}
+	db.usable = db.pageSize - int(data[20])
+	if db.usable < 480 {
+		return nil, fmt.Errorf("invalid reserved space: %d", data[20])
+	}
+	if enc := binary.BigEndian.Uint32(data[56:]); enc > 1 {
+		return nil, fmt.Errorf("unsupported text encoding: %d", enc)
+	}
+	return db, nil
+}
+
+// readWAL indexes pages of committed transactions in the write-ahead log. Frames are not verified with checksums,
+// but frames from previous generations of the log are ignored. Invalid logs are ignored as well.
+func (db *sqliteDB) readWAL(data []byte) {
+	if len(data) < walHeaderSize {
+		return
+	}
+	if magic := binary.BigEndian.Uint32(data); magic&^1 != 0x377f0682 {
+		return
+	}
+	if int(binary.BigEndian.Uint32(data[8:])) != db.pageSize {
+		return
+	}
+	salt := data[16:24]
+	pages := make(map[int]int)
+	size := 0
+	var pending [][2]int
+	for off := walHeaderSize; off+walFrameHeaderSize+db.pageSize <= len(data); off += walFrameHeaderSize + db.pageSize {
+		h := data[off : off+walFrameHeaderSize]
+		if !bytes.Equal(h[8:16], salt) {
+			break
+		}
+		pending = append(pending, [2]int{int(binary.BigEndian.Uint32(h)), off + walFrameHeaderSize})
+		// commit frames store the database size after the commit, which truncates the database if it shrinks
+		if n := binary.BigEndian.Uint32(h[4:]); n != 0 {
+			for _, p := range pending {
+				pages[p[0]] = p[1]
+			}
+			pending = pending[:0]
+			size = int(n)
+		}
+	}
+	if len(pages) != 0 {
+		db.wal, db.walData, db.walSize = pages, data, size
+	}
+}
+
+// page returns the content of the page with a given number, starting from 1.
+func (db *sqliteDB) page(n int) ([]byte, error) {
+	if n < 1 || (db.wal != nil && n > db.walSize) {
+		return nil, fmt.Errorf("page %d is out of range", n)
+	}
+	if off, ok := db.wal[n]; ok {
+		return db.walData[off : off+db.pageSize], nil
+	}
+	off := (n - 1) * db.pageSize
+	if off+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("page %d is out of range", n)
+	}
+	return db.data[off : off+db.pageSize], nil
+}
+
+// pages returns the number of pages in the database.
+func (db *sqliteDB) pages() int {
+	if db.wal != nil {
+		return db.walSize
+	}
+	return len(db.data) / db.pageSize
+}
+
+// varint decodes a variable-length integer. It returns the value and the number of bytes read.
+func varint(b []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return int64(v<<8 | uint64(b[i])), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return int64(v), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// walkTable calls fn for each row of the table B-tree with a given root page.
+// For WITHOUT ROWID tables the index B-tree is walked instead, and the row ID is always zero.
+func (db *sqliteDB) walkTable(root int, index bool, fn func(rowid int64, payload []byte) error) error {
+	w := &treeWalker{db: db, index: index, seen: make(map[int]bool), fn: fn}
+	return w.walk(root, 0)
+}
+
+type treeWalker struct {
+	db    *sqliteDB
+	index bool
+	seen  map[int]bool // pages already visited, to detect loops in corrupted files
+	fn    func(rowid int64, payload []byte) error
+}
+
+func (w *treeWalker) walk(n, depth int) error {
+	db := w.db
+	if depth > 64 {
+		return errors.New("b-tree is too deep")
+	}
+	if w.seen[n] {
+		return fmt.Errorf("page %d is referenced twice", n)
+	}
+	w.seen[n] = true
+	p, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	hdr := p
+	if n == 1 {
+		hdr = p[sqliteHeaderSize:]
+	}
+	typ := hdr[0]
+	var interior bool
+	switch typ {
+	case pageInteriorTable, pageInteriorIndex:
+		interior = true
+	case pageLeafTable, pageLeafIndex:
+	default:
+		return fmt.Errorf("page %d: unexpected page type: %#x", n, typ)
+	}
+	if isIndex := typ == pageInteriorIndex || typ == pageLeafIndex; isIndex != w.index {
+		return fmt.Errorf("page %d: unexpected page type: %#x", n, typ)
+	}
+	cells := int(binary.BigEndian.Uint16(hdr[3:]))
+	hsize := 8
+	if interior {
+		hsize = 12
+	}
+	ptrs := hdr[hsize:]
+	if len(ptrs) < 2*cells {
+		return fmt.Errorf("page %d: invalid cell count", n)
+	}
+	for i := 0; i < cells; i++ {
+		off := int(binary.BigEndian.Uint16(ptrs[2*i:]))
+		if off >= len(p) {
+			return fmt.Errorf("page %d: invalid cell offset", n)
+		}
+		cell := p[off:]
+		if interior {
+			if len(cell) < 4 {
+				return fmt.Errorf("page %d: invalid cell", n)
+			}
+			if err := w.walk(int(binary.BigEndian.Uint32(cell)), depth+1); err != nil {
+				return err
+			}
+			if !w.index {
+				continue // only keys are stored in interior table pages
+			}
+			cell = cell[4:]
+		}
+		var rowid int64
+		size, k := varint(cell)
+		if k == 0 {
+			return fmt.Errorf("page %d: invalid cell", n)
+		}
+		cell = cell[k:]
+		if !w.index {
+			rowid, k = varint(cell)
+			if k == 0 {
+				return fmt.Errorf("page %d: invalid cell", n)
+			}
+			cell = cell[k:]
+		}
+		payload, err := db.payload(cell, size, w.index)
+		if err != nil {
+			return fmt.Errorf("page %d: %v", n, err)
+		}
+		if err := w.fn(rowid, payload); err != nil {
+			return err
+		}
+	}
+	if interior {
+		return w.walk(int(binary.BigEndian.Uint32(hdr[8:])), depth+1)
+	}
+	return nil
+}
+
+// payload reads the cell payload of a given size, following overflow pages if necessary.
+func (db *sqliteDB) payload(b []byte, size int64, index bool) ([]byte, error) {
+	u := db.usable
+	if size < 0 || size > int64(db.pages())*int64(u) {
+		return nil, errors.New("invalid payload size")
+	}
+	n := int(size)
+	maxLocal := u - 35
+	if index {
+		maxLocal = (u-12)*64/255 - 23
+	}
+	if n <= maxLocal {
+		if n > len(b) {
+			return nil, errors.New("payload is out of range")
+		}
+		return b[:n], nil
+	}
+	minLocal := (u-12)*32/255 - 23
+	local := minLocal + (n-minLocal)%(u-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	if local+4 > len(b) {
+		return nil, errors.New("payload is out of range")
+	}
+	out := make([]byte, 0, n)
+	out = append(out, b[:local]...)
+	next := int(binary.BigEndian.Uint32(b[local:]))
+	for len(out) < n {
+		if next == 0 {
+			return nil, errors.New("overflow chain is too short")
+		}
+		p, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = int(binary.BigEndian.Uint32(p))
+		chunk := p[4:u]
+		if rest := n - len(out); len(chunk) > rest {
+			chunk = chunk[:rest]
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+// parseRecord decodes column values of a record. Values are nil, int64, float64, string or []byte.
+func parseRecord(b []byte) ([]interface{}, error) {
+	hsize, k := varint(b)
+	if k == 0 || int(hsize) > len(b) || int(hsize) < k {
+		return nil, errors.New("invalid record header")
+	}
+	hdr, body := b[k:hsize], b[hsize:]
+	var out []interface{}
+	for len(hdr) > 0 {
+		typ, k := varint(hdr)
+		if k == 0 {
+			return nil, errors.New("invalid record header")
+		}
+		hdr = hdr[k:]
+		var n int
+		switch {
+		case typ >= 12:
+			n = int(typ-12) / 2
+		case typ >= 1 && typ <= 4:
+			n = int(typ)
+		case typ == 5:
+			n = 6
+		case typ == 6 || typ == 7:
+			n = 8
+		}
+		if n > len(body) {
+			return nil, errors.New("record is out of range")
+		}
+		v := body[:n]
+		body = body[n:]
+		switch {
+		case typ == 0:
+			out = append(out, nil)
+		case typ >= 1 && typ <= 6:
+			// big-endian two's complement integer of n bytes
+			x := int64(int8(v[0]))
+			for _, c := range v[1:] {
+				x = x<<8 | int64(c)
+			}
+			out = append(out, x)
+		case typ == 7:
+			out = append(out, math.Float64frombits(binary.BigEndian.Uint64(v)))
+		case typ == 8 || typ == 9:
+			out = append(out, int64(typ-8))
+		case typ >= 12 && typ%2 == 0:
+			out = append(out, v)
+		case typ >= 13:
+			out = append(out, string(v))
+		default:
+			return nil, fmt.Errorf("unsupported serial type: %d", typ)
+		}
+	}
+	return out, nil
+}
+
+// sqliteTable describes a table from the schema.
+type sqliteTable struct {
+	Name string
+	Root int
+	// Columns are listed in the order they are stored in records. For WITHOUT ROWID tables
+	// it differs from the declaration order. Virtual generated columns are not listed.
+	Columns []string
+	// RowID is an index of the column which is an alias for the row ID, or -1.
+	RowID int
+	// WithoutRowID is set for tables stored in an index B-tree.
+	WithoutRowID bool
+}
+
+// tables reads the schema of the database.
+func (db *sqliteDB) tables() (map[string]*sqliteTable, error) {
+	out := make(map[string]*sqliteTable)
+	err := db.walkTable(1, false, func(_ int64, payload []byte) error {
+		rec, err := parseRecord(payload)
+		if err != nil {
+			return err
+		}
+		if len(rec) < 5 {
+			return errors.New("invalid schema")
+		}
+		typ, _ := rec[0].(string)
+		name, _ := rec[1].(string)
+		root, _ := rec[3].(int64)
+		sql, _ := rec[4].(string)
+		if typ != "table" || root == 0 {
+			return nil // index, view or a virtual table
+		}
+		t := parseTable(sql)
+		t.Name, t.Root = name, int(root)
+		out[name] = t
+		return nil
+	})
+	return out, err
+}
+
+// sqlToken is a token of an SQL statement. Quoted identifiers and string literals are unquoted.
+type sqlToken struct {
+	Text   string
+	Quoted bool
+}
+
+// is checks if the token is a given keyword or punctuation.
+func (t sqlToken) is(s string) bool {
+	return !t.Quoted && strings.EqualFold(t.Text, s)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// tokenizeSQL splits an SQL statement into tokens, skipping whitespace and comments.
+func tokenizeSQL(sql string) []sqlToken {
+	var out []sqlToken
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(sql[i:], "--"):
+			if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(sql)
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+				i += j + 4
+			} else {
+				i = len(sql)
+			}
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == end {
+					if end == ']' || j+1 >= len(sql) || sql[j+1] != end {
+						break
+					}
+					j++ // doubled quote
+				}
+				b.WriteByte(sql[j])
+			}
+			out = append(out, sqlToken{Text: b.String(), Quoted: true})
+			i = j + 1
+		case isIdentChar(c):
+			j := i
+			for j < len(sql) && isIdentChar(sql[j]) {
+				j++
+			}
+			out = append(out, sqlToken{Text: sql[i:j]})
+			i = j
+		default:
+			out = append(out, sqlToken{Text: sql[i : i+1]})
+			i++
+		}
+	}
+	return out
+}
+
+// splitList splits tokens by commas, ignoring ones in parentheses, e.g. in "DECIMAL(10,5)".
+// It stops at the closing parenthesis of the list and returns the remaining tokens after it.
+func splitList(toks []sqlToken) (list [][]sqlToken, rest []sqlToken) {
+	depth, last := 0, 0
+	for i, t := range toks {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			if depth == 0 {
+				return append(list, toks[last:i]), toks[i+1:]
+			}
+			depth--
+		case t.is(",") && depth == 0:
+			list = append(list, toks[last:i])
+			last = i + 1
+		}
+	}
+	return append(list, toks[last:]), nil
+}
+
+// columnConstraints are keywords that end the type name of a column.
+var columnConstraints = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "NOT": true, "NULL": true, "UNIQUE": true, "CHECK": true,
+	"DEFAULT": true, "COLLATE": true, "REFERENCES": true, "GENERATED": true, "AS": true,
+}
+
+// parseTable extracts columns from the CREATE TABLE statement.
+// See https://www.sqlite.org/lang_createtable.html for the details on row ID aliases and WITHOUT ROWID tables.
+func parseTable(sql string) *sqliteTable {
+	t := &sqliteTable{RowID: -1}
+	toks := tokenizeSQL(sql)
+	start := -1
+	for i, tok := range toks {
+		if tok.is("(") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return t
+	}
+	defs, rest := splitList(toks[start+1:])
+	for i := 0; i+1 < len(rest); i++ {
+		if rest[i].is("WITHOUT") && rest[i+1].is("ROWID") {
+			t.WithoutRowID = true
+		}
+	}
+
+	var (
+		types  []string
+		pk     []string // columns of the primary key
+		pkDesc bool     // primary key is declared as a column constraint with DESC
+	)
+	for _, def := range defs {
+		if len(def) == 0 {
+			continue
+		}
+		name := def[0]
+		if name.is("CONSTRAINT") && len(def) > 2 {
+			def = def[2:] // named table constraint
+			name = def[0]
+		}
+		switch {
+		case name.is("PRIMARY"):
+			for j, tok := range def {
+				if tok.is("(") {
+					cols, _ := splitList(def[j+1:])
+					pk, pkDesc = nil, false
+					for _, c := range cols {
+						if len(c) != 0 {
+							pk = append(pk, c[0].Text)
+						}
+					}
+					break
+				}
+			}
+			continue
+		case name.is("UNIQUE"), name.is("CHECK"), name.is("FOREIGN"):
+			continue // table constraint
+		}
+		var (
+			typ               []string
+			generated, stored bool
+			depth             int
+			inType            = true
+		)
+		for j := 1; j < len(def); j++ {
+			tok := def[j]
+			switch {
+			case tok.is("("):
+				depth++
+			case tok.is(")"):
+				depth--
+			case depth != 0:
+			case !tok.Quoted && columnConstraints[strings.ToUpper(tok.Text)]:
+				inType = false
+				if tok.is("AS") {
+					generated = true
+				} else if tok.is("PRIMARY") {
+					pk, pkDesc = []string{name.Text}, j+2 < len(def) && def[j+2].is("DESC")
+				}
+			case tok.is("STORED"):
+				stored = true
+			}
+			if inType && depth == 0 && !tok.is(")") {
+				typ = append(typ, strings.ToUpper(tok.Text))
+			}
+		}
+		if generated && !stored {
+			continue // virtual columns are not stored in records
+		}
+		t.Columns = append(t.Columns, name.Text)
+		types = append(types, strings.Join(typ, " "))
+	}
+
+	if t.WithoutRowID {
+		// columns of the primary key are stored first
+		var cols []string
+		for _, name := range pk {
+			for i, c := range t.Columns {
+				if c != "" && strings.EqualFold(c, name) {
+					cols = append(cols, c)
+					t.Columns[i] = ""
+					break
+				}
+			}
+		}
+		for _, c := range t.Columns {
+			if c != "" {
+				cols = append(cols, c)
+			}
+		}
+		t.Columns = cols
+		return t
+	}
+	if len(pk) == 1 && !pkDesc {
+		for i, c := range t.Columns {
+			if strings.EqualFold(c, pk[0]) && types[i] == "INTEGER" {
+				t.RowID = i
+			}
+		}
+	}
+	return t
+}
+
+// rows calls fn for each row of the table. Rows are passed as maps from column names to values.
+func (db *sqliteDB) rows(t *sqliteTable, fn func(row map[string]interface{}) error) error {
+	return db.walkTable(t.Root, t.WithoutRowID, func(rowid int64, payload []byte) error {
+		rec, err := parseRecord(payload)
+		if err != nil {
+			return fmt.Errorf("%s: %v", t.Name, err)
+		}
+		row := make(map[string]interface{}, len(t.Columns))
+		for i, name := range t.Columns {
+			if i == t.RowID {
+				row[name] = rowid
+			} else if i < len(rec) {
+				// columns added later with ALTER TABLE may be missing in old rows
+				row[name] = rec[i]
+			}
+		}
+		return fn(row)
+	})
+}
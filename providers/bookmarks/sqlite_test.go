@@ -0,0 +1,249 @@
+package bookmarks
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarint(t *testing.T) {
+	cases := []struct {
+		data []byte
+		v    int64
+		n    int
+	}{
+		{data: []byte{0x05}, v: 5, n: 1},
+		{data: []byte{0x81, 0x00}, v: 128, n: 2},
+		{data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, v: -1, n: 9},
+		{data: []byte{0x81}, v: 0, n: 0},
+	}
+	for _, c := range cases {
+		v, n := varint(c.data)
+		require.Equal(t, c.v, v)
+		require.Equal(t, c.n, n)
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	cases := []struct {
+		name  string
+		sql   string
+		cols  []string
+		rowid int
+	}{
+		{
+			name:  "constraints",
+			sql:   `CREATE TABLE "t" (a DECIMAL(10,5), "b" INTEGER PRIMARY KEY, [c] TEXT, PRIMARY KEY (b), UNIQUE (a, c))`,
+			cols:  []string{"a", "b", "c"},
+			rowid: 1,
+		},
+		{
+			name:  "quoted",
+			sql:   "CREATE TABLE `a (b)` (\"x, y\" TEXT DEFAULT ')', [p k] INT, `z``` BLOB CHECK (z != ',')) -- (c)",
+			cols:  []string{"x, y", "p k", "z`"},
+			rowid: -1,
+		},
+		{
+			name:  "not integer",
+			sql:   `CREATE TABLE t (id INT PRIMARY KEY, v)`,
+			cols:  []string{"id", "v"},
+			rowid: -1,
+		},
+		{
+			name:  "desc",
+			sql:   `CREATE TABLE t (id INTEGER PRIMARY KEY DESC, v)`,
+			cols:  []string{"id", "v"},
+			rowid: -1,
+		},
+		{
+			name:  "table key",
+			sql:   `CREATE TABLE t (v TEXT, id integer, CONSTRAINT "primary" PRIMARY KEY (id DESC))`,
+			cols:  []string{"v", "id"},
+			rowid: 1,
+		},
+		{
+			name:  "generated",
+			sql:   `CREATE TABLE t (a INTEGER, b GENERATED ALWAYS AS (a*2) STORED, c AS (a*3), d)`,
+			cols:  []string{"a", "b", "d"},
+			rowid: -1,
+		},
+		{
+			name:  "without rowid",
+			sql:   `CREATE TABLE t (a, b INTEGER, c, PRIMARY KEY (c, b)) WITHOUT ROWID`,
+			cols:  []string{"c", "b", "a"},
+			rowid: -1,
+		},
+		{
+			name:  "invalid",
+			sql:   `CREATE TABLE`,
+			rowid: -1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tbl := parseTable(c.sql)
+			require.Equal(t, c.cols, tbl.Columns)
+			require.Equal(t, c.rowid, tbl.RowID)
+			require.Equal(t, c.name == "without rowid", tbl.WithoutRowID)
+		})
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	db, err := openSQLite("testdata/places.sqlite")
+	require.NoError(t, err)
+	tables, err := db.tables()
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+	places := tables["moz_places"]
+	require.Equal(t, 0, places.RowID)
+	require.Equal(t, "url", places.Columns[1])
+
+	var rows []map[string]interface{}
+	err = db.rows(places, func(row map[string]interface{}) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 600)
+	for i, row := range rows {
+		require.Equal(t, int64(i+1), row["id"])
+	}
+	require.Equal(t, map[string]interface{}{
+		"id": int64(1), "url": "https://go.dev/", "title": "The Go Programming Language", "rev_host": nil,
+		"visit_count": int64(42), "hidden": int64(0), "typed": int64(0), "frecency": int64(-1),
+		"last_visit_date": int64(1709294400000000), "guid": "place1", "foreign_count": int64(0), "url_hash": int64(0),
+		"description": nil, "preview_image_url": nil, "site_name": nil, "origin_id": nil,
+		"recalc_frecency": int64(0), "alt_frecency": nil, "recalc_alt_frecency": int64(0),
+	}, rows[0])
+
+	// the title does not fit into a page and is stored in overflow pages
+	title := rows[4]["title"].(string)
+	require.Len(t, title, 5+3000)
+	require.Equal(t, "Long "+strings.Repeat("ab", 1500), title)
+
+	_, err = openSQLite("testdata/bookmarks.html")
+	require.Error(t, err)
+}
+
+func TestSQLiteWAL(t *testing.T) {
+	db, err := openSQLite("testdata/wal/places.sqlite")
+	require.NoError(t, err)
+	require.NotEmpty(t, db.wal)
+	tables, err := db.tables()
+	require.NoError(t, err)
+	var visits []int64
+	err = db.rows(tables["moz_places"], func(row map[string]interface{}) error {
+		visits = append(visits, row["visit_count"].(int64))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 3}, visits)
+}
+
+func TestSQLiteSchema(t *testing.T) {
+	db, err := openSQLite("testdata/schema.sqlite")
+	require.NoError(t, err)
+	tables, err := db.tables()
+	require.NoError(t, err)
+
+	var rows []map[string]interface{}
+	err = db.rows(tables["odd, (table)"], func(row map[string]interface{}) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{
+		{"first name": "Ada", "last,name": "Lovelace", "id": int64(7), "Primary": "yes"},
+	}, rows)
+
+	kv := tables["kv"]
+	require.True(t, kv.WithoutRowID)
+	rows = nil
+	err = db.rows(kv, func(row map[string]interface{}) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 300)
+	// rows are sorted by the primary key
+	require.Equal(t, map[string]interface{}{"grp": int64(0), "k": "key102", "v": "value102"}, rows[0])
+	var long string
+	for _, row := range rows {
+		if row["k"] == "key100" {
+			long = row["v"].(string)
+		}
+	}
+	require.Equal(t, strings.Repeat("xy", 1000), long)
+}
+
+func TestSQLiteWALShrink(t *testing.T) {
+	// the database was truncated by a transaction in the log, pages after the end are stale
+	db, err := openSQLite("testdata/wal/shrink.sqlite")
+	require.NoError(t, err)
+	require.True(t, len(db.data)/db.pageSize > db.pages())
+	_, err = db.page(db.pages() + 1)
+	require.Error(t, err)
+
+	tables, err := db.tables()
+	require.NoError(t, err)
+	var ids []int64
+	err = db.rows(tables["t"], func(row map[string]interface{}) error {
+		ids = append(ids, row["id"].(int64))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+}
+
+// FuzzSQLitePage replaces a single page of test databases with corrupted data.
+// Reading the database must fail or succeed, but never panic or hang.
+func FuzzSQLitePage(f *testing.F) {
+	var files [][]byte
+	for _, name := range []string{"testdata/places.sqlite", "testdata/schema.sqlite"} {
+		data, err := os.ReadFile(name)
+		require.NoError(f, err)
+		files = append(files, data)
+		const pageSize = 1024
+		for i := 0; i*pageSize < len(data); i++ {
+			f.Add(uint8(len(files)-1), uint16(i), data[i*pageSize:(i+1)*pageSize])
+		}
+	}
+	f.Fuzz(func(t *testing.T, file uint8, i uint16, page []byte) {
+		data := append([]byte{}, files[int(file)%len(files)]...)
+		off := int(i) % (len(data) / 1024) * 1024
+		copy(data[off:off+1024], page)
+		db, err := newSQLite(data)
+		if err != nil {
+			return
+		}
+		tables, err := db.tables()
+		if err != nil {
+			return
+		}
+		for _, tbl := range tables {
+			_ = db.rows(tbl, func(row map[string]interface{}) error {
+				return nil
+			})
+		}
+	})
+}
+
+func FuzzParseRecord(f *testing.F) {
+	f.Add([]byte{0x03, 0x01, 0x13, 0x2a, 'a', 'b', 'c'})
+	f.Add([]byte{0x02, 0x07, 0x40, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parseRecord(data)
+	})
+}
+
+func FuzzParseTable(f *testing.F) {
+	f.Add(`CREATE TABLE "t" (a DECIMAL(10,5), "b" INTEGER PRIMARY KEY, [c] TEXT, PRIMARY KEY (b), UNIQUE (a, c))`)
+	f.Add(`CREATE TABLE t (a, b INTEGER, c, PRIMARY KEY (c, b)) WITHOUT ROWID`)
+	f.Fuzz(func(t *testing.T, sql string) {
+		tbl := parseTable(sql)
+		require.True(t, tbl.RowID < len(tbl.Columns))
+	})
+}
@@ -0,0 +1,102 @@
+#!/bin/sh
+# Generates browser history databases used in tests. Requires the sqlite3 command.
+# Schemas are reduced versions of ones used by Firefox and Chromium.
+set -e
+cd "$(dirname "$0")"
+rm -f places.sqlite History schema.sqlite wal/*
+
+FIREFOX_SCHEMA='
+PRAGMA page_size = 1024;
+CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR, rev_host LONGVARCHAR, visit_count INTEGER DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL, typed INTEGER DEFAULT 0 NOT NULL, frecency INTEGER DEFAULT -1 NOT NULL, last_visit_date INTEGER , guid TEXT, foreign_count INTEGER DEFAULT 0 NOT NULL, url_hash INTEGER DEFAULT 0 NOT NULL , description TEXT, preview_image_url TEXT, site_name TEXT, origin_id INTEGER REFERENCES moz_origins(id), recalc_frecency INTEGER NOT NULL DEFAULT 0, alt_frecency INTEGER, recalc_alt_frecency INTEGER NOT NULL DEFAULT 0);
+CREATE TABLE moz_bookmarks (id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL, parent INTEGER, position INTEGER, title LONGVARCHAR, keyword_id INTEGER, folder_type TEXT, dateAdded INTEGER, lastModified INTEGER, guid TEXT, syncStatus INTEGER NOT NULL DEFAULT 0, syncChangeCounter INTEGER NOT NULL DEFAULT 1);
+CREATE UNIQUE INDEX moz_places_guid_uniqueindex ON moz_places (guid);
+CREATE INDEX moz_bookmarks_itemindex ON moz_bookmarks (fk, type);
+'
+
+sqlite3 places.sqlite "$FIREFOX_SCHEMA" "
+INSERT INTO moz_places (id, url, title, visit_count, hidden, last_visit_date, guid) VALUES
+  (1, 'https://go.dev/', 'The Go Programming Language', 42, 0, 1709294400000000, 'place1'),
+  (2, 'https://pkg.go.dev/net/http', 'http package - net/http', 7, 0, 1709208000000000, 'place2'),
+  (3, 'https://example.com/redirect', NULL, 3, 1, 1709208000000000, 'place3'),
+  (4, 'https://www.mozilla.org/', NULL, 0, 0, NULL, 'place4'),
+  (5, 'https://long.example/', 'Long ' || replace(hex(zeroblob(1500)), '00', 'ab'), 1, 0, 1709000000000000, 'place5');
+-- enough rows to make a b-tree with interior pages
+WITH RECURSIVE n(i) AS (SELECT 6 UNION ALL SELECT i+1 FROM n WHERE i < 600)
+INSERT INTO moz_places (id, url, hidden, guid) SELECT i, 'https://filler.example/' || i, 1, 'filler' || i FROM n;
+INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, guid) VALUES
+  (1, 2, NULL, 0, 0, '', 'root________'),
+  (2, 2, NULL, 1, 0, 'menu', 'menu________'),
+  (3, 2, NULL, 1, 1, 'toolbar', 'toolbar_____'),
+  (4, 2, NULL, 1, 2, 'tags', 'tags________'),
+  (5, 2, NULL, 1, 3, 'unfiled', 'unfiled_____'),
+  (10, 2, NULL, 3, 0, 'Go', 'folder10'),
+  (11, 2, NULL, 10, 1, 'Docs', 'folder11'),
+  (20, 1, 1, 10, 0, 'Go', 'mark20'),
+  (21, 1, 2, 11, 0, NULL, 'mark21'),
+  (22, 1, 4, 2, 0, 'Mozilla', 'mark22'),
+  (30, 2, NULL, 4, 0, 'golang', 'tag30'),
+  (31, 1, 1, 30, 0, NULL, 'mark31');
+"
+
+sqlite3 History "
+CREATE TABLE meta(key LONGVARCHAR NOT NULL UNIQUE PRIMARY KEY, value LONGVARCHAR);
+CREATE TABLE urls(id INTEGER PRIMARY KEY AUTOINCREMENT,url LONGVARCHAR,title LONGVARCHAR,visit_count INTEGER DEFAULT 0 NOT NULL,typed_count INTEGER DEFAULT 0 NOT NULL,last_visit_time INTEGER NOT NULL,hidden INTEGER DEFAULT 0 NOT NULL);
+CREATE INDEX urls_url_index ON urls (url);
+INSERT INTO meta VALUES ('version', '67');
+INSERT INTO urls (url, title, visit_count, last_visit_time, hidden) VALUES
+  ('https://go.dev/', 'The Go Programming Language', 10, 13353768000000000, 0),
+  ('https://github.com/golang/go', 'golang/go: The Go programming language', 25, 13353681600000000, 0),
+  ('https://accounts.example.com/login', 'Login', 2, 13353681600000000, 1);
+"
+
+# Tables with unusual definitions, including a WITHOUT ROWID table which is stored in an index B-tree.
+sqlite3 schema.sqlite "
+PRAGMA page_size = 1024;
+CREATE TABLE \"odd, (table)\" (
+  \"first name\" TEXT DEFAULT 'a, (b' NOT NULL, -- comment, with a comma
+  [last,name] VARCHAR(10, 5) CHECK (length([last,name]) > 0),
+  \"full\" TEXT AS (\"first name\" || ' ' || [last,name]) VIRTUAL,
+  /* PRIMARY KEY (x) */ \"id\" INTEGER,
+  \"Primary\" TEXT,
+  CONSTRAINT pk PRIMARY KEY (\"id\" ASC),
+  UNIQUE (\"first name\", [last,name])
+);
+INSERT INTO \"odd, (table)\" VALUES ('Ada', 'Lovelace', 7, 'yes');
+CREATE TABLE kv (k TEXT, grp INTEGER, v TEXT, PRIMARY KEY (grp, k)) WITHOUT ROWID;
+-- enough rows and long values to make a b-tree with interior pages and overflow pages
+WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i+1 FROM n WHERE i < 300)
+INSERT INTO kv SELECT 'key' || i, i % 3, CASE WHEN i = 100 THEN replace(hex(zeroblob(1000)), '00', 'xy') ELSE 'value' || i END FROM n;
+"
+
+# Committed transactions stay in the write-ahead log until a checkpoint.
+# Files are copied while the database is still open, before it is checkpointed on close.
+sqlite3 wal/db.sqlite > /dev/null <<SQL
+$FIREFOX_SCHEMA
+PRAGMA journal_mode = WAL;
+PRAGMA wal_autocheckpoint = 0;
+INSERT INTO moz_places (id, url, title, visit_count, last_visit_date) VALUES
+  (1, 'https://go.dev/', 'The Go Programming Language', 1, 1709294400000000);
+PRAGMA wal_checkpoint;
+INSERT INTO moz_places (id, url, title, visit_count, last_visit_date) VALUES
+  (2, 'https://go.dev/blog/', 'The Go Blog', 3, 1709294400000000);
+UPDATE moz_places SET visit_count = 2 WHERE id = 1;
+.shell cp wal/db.sqlite wal/places.sqlite
+.shell cp wal/db.sqlite-wal wal/places.sqlite-wal
+SQL
+rm -f wal/db.sqlite wal/db.sqlite-wal wal/db.sqlite-shm
+
+# The database shrinks after VACUUM, but the main file keeps old pages until a checkpoint.
+sqlite3 wal/db.sqlite > /dev/null <<SQL
+PRAGMA page_size = 1024;
+PRAGMA journal_mode = WAL;
+PRAGMA wal_autocheckpoint = 0;
+CREATE TABLE t (id INTEGER PRIMARY KEY, v TEXT);
+WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i+1 FROM n WHERE i < 30)
+INSERT INTO t SELECT i, hex(zeroblob(200)) FROM n;
+PRAGMA wal_checkpoint;
+DELETE FROM t WHERE id > 2;
+VACUUM;
+.shell cp wal/db.sqlite wal/shrink.sqlite
+.shell cp wal/db.sqlite-wal wal/shrink.sqlite-wal
+SQL
+rm -f wal/db.sqlite wal/db.sqlite-wal wal/db.sqlite-shm
@@ -0,0 +1,230 @@
+package brave
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+var _ search.Service = (*Service)(nil)
+
+const (
+	provName   = "brave"
+	baseURL    = "https://search.brave.com"
+	searchPath = "/search"
+	maxPages   = 10 // Brave doesn't serve results past this page
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query:      req.Query,
+		Country:    toCountry(req.Lang, req.Region),
+		SafeSearch: req.Safe,
+	}
+	if !req.Lang.IsRoot() {
+		r.Language = req.Lang.String()
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, fetched: true, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	fetched bool
+
+	page []Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Page+1 >= maxPages {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Results
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return &search.LinkResult{
+		URL: *u, Title: r.Title, Desc: r.Content,
+	}
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query      string `json:"q"`
+	Page       int    `json:"page"`
+	Language   string `json:"lang"`
+	Country    string `json:"country"`
+	SafeSearch bool   `json:"safe"`
+}
+
+type Result struct {
+	Title   string
+	URL     string
+	Content string
+}
+
+type SearchResp struct {
+	Results []Result
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("source", "web")
+	if r.Page > 0 {
+		params.Set("offset", strconv.Itoa(r.Page))
+	}
+	req, err := s.GetRequest(searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	safe := "off"
+	if r.SafeSearch {
+		safe = "strict"
+	}
+	req.AddCookie(&http.Cookie{Name: "safesearch", Value: safe})
+	if r.Country != "" {
+		req.AddCookie(&http.Cookie{Name: "country", Value: r.Country})
+		req.AddCookie(&http.Cookie{Name: "useLocation", Value: "0"})
+	}
+	if r.Language != "" {
+		req.AddCookie(&http.Cookie{Name: "ui_lang", Value: strings.ToLower(r.Language)})
+		req.Header.Set("Accept-Language", r.Language)
+	}
+	doc, err := s.DoHTML(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	out := &SearchResp{}
+	doc.Find(`div.snippet[data-type="web"]`).Each(func(_ int, sel *goquery.Selection) {
+		a := sel.Find(`a[href]`).First()
+		link := a.AttrOr("href", "")
+		if !strings.HasPrefix(link, "http") {
+			return
+		}
+		title := sel.Find(`.title`).First().Text()
+		content := sel.Find(`.snippet-description`).First()
+		if content.Size() == 0 {
+			content = sel.Find(`.snippet-content .content`).First()
+		}
+		out.Results = append(out.Results, Result{
+			Title:   strings.TrimSpace(title),
+			URL:     link,
+			Content: strings.TrimSpace(content.Text()),
+		})
+	})
+	return out, nil
+}
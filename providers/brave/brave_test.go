@@ -0,0 +1,83 @@
+package brave
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []*http.Request
+	page := providertest.ServeFile(t, "testdata/solar.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "search.brave.com", r.Host)
+		reqs = append(reqs, r)
+		if r.URL.Query().Get("offset") == "2" {
+			w.Write([]byte(`<html><body><main id="results"></main></body></html>`))
+			return
+		}
+		page(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{
+		Query: "solar",
+		Lang:  search.MustParseLangCode("de-AT"),
+		Safe:  true,
+	})
+	defer it.Close()
+	var (
+		got []search.Result
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+		if len(got) == 3 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 4)
+	require.Equal(t, "Solar energy - Wikipedia", got[0].GetTitle())
+	require.Equal(t, "https://en.wikipedia.org/wiki/Solar_energy", got[0].GetURL().String())
+	require.Equal(t, "Solar energy is radiant light and heat from the Sun.", got[0].GetDesc())
+	require.Equal(t, "Research and development of solar technologies.", got[1].GetDesc())
+
+	require.Len(t, reqs, 3)
+	require.Equal(t, "", reqs[0].URL.Query().Get("offset"))
+	require.Equal(t, "1", reqs[1].URL.Query().Get("offset"))
+	for name, exp := range map[string]string{
+		"safesearch": "strict",
+		"country":    "at",
+		"ui_lang":    "de-at",
+	} {
+		c, err := reqs[0].Cookie(name)
+		require.NoError(t, err, name)
+		require.Equal(t, exp, c.Value, name)
+	}
+
+	reqs = nil
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	got = nil
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 1)
+	require.Equal(t, "https://www.energy.gov/solar", got[0].GetURL().String())
+	require.Len(t, reqs, 2)
+}
+
+func TestToCountry(t *testing.T) {
+	require.Equal(t, "", toCountry(search.LangCode{}, search.RegionCode{}))
+	require.Equal(t, "de", toCountry(search.MustParseLangCode("de"), search.RegionCode{}))
+	require.Equal(t, "ch", toCountry(search.MustParseLangCode("de"), search.MustParseRegionCode("CH")))
+	require.Equal(t, "", toCountry(search.MustParseLangCode("uk"), search.RegionCode{}))
+}
@@ -0,0 +1,63 @@
+package brave
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language/display"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+// countries supported by Brave Search.
+var countries = []string{
+	"AR", "AU", "AT", "BE", "BR", "CA", "CL", "DK", "FI", "FR", "DE", "HK",
+	"IN", "ID", "IT", "JP", "KR", "MY", "MX", "NL", "NZ", "NO", "CN", "PL",
+	"PT", "PH", "RU", "SA", "ZA", "ES", "SE", "CH", "TW", "TR", "GB", "US",
+}
+
+// languages supported by Brave Search.
+var languages = []string{
+	"ar", "eu", "bn", "bg", "ca", "zh-Hans", "zh-Hant", "hr", "cs", "da", "nl",
+	"en", "en-GB", "et", "fi", "fr", "gl", "de", "gu", "he", "hi", "hu", "is",
+	"it", "ja", "kn", "ko", "lv", "lt", "ms", "ml", "mr", "nb", "pl", "pt-BR",
+	"pt-PT", "pa", "ro", "ru", "sr", "sk", "sl", "es", "sv", "ta", "te", "th",
+	"tr", "uk", "vi",
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	names := display.English.Tags()
+	out := make([]search.Language, 0, len(languages))
+	for _, l := range languages {
+		code := search.MustParseLangCode(l)
+		out = append(out, search.Language{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	names := display.English.Regions()
+	out := make([]search.Region, 0, len(countries))
+	for _, c := range countries {
+		code := search.MustParseRegionCode(c)
+		out = append(out, search.Region{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+// toCountry returns a Brave country code for a given language and region.
+func toCountry(lang search.LangCode, reg search.RegionCode) string {
+	if reg == (search.RegionCode{}) {
+		if lang.IsRoot() {
+			return ""
+		}
+		reg, _ = lang.Region()
+	}
+	c := reg.String()
+	for _, v := range countries {
+		if v == c {
+			return strings.ToLower(c)
+		}
+	}
+	return ""
+}
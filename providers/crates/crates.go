@@ -0,0 +1,232 @@
+package crates
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "crates"
+	baseURL    = "https://crates.io"
+	searchPath = "/api/v1/crates"
+	crateURL   = baseURL + "/crates/"
+)
+
+var (
+	DefaultPerPage = 20
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, Page: 1}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, more: resp.Meta.NextPage != "", fetched: true, page: resp.Crates, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	more    bool
+	fetched bool
+
+	page []Crate
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || !it.more {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Crates
+	it.more = resp.Meta.NextPage != ""
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page"`
+}
+
+// Crate is a search result from crates.io. Only RecentDownloads (the last 90 days) is reported
+// as PackageResult.Downloads, so it is zero for crates that were not downloaded recently.
+type Crate struct {
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	MaxVersion       string    `json:"max_version"`
+	MaxStableVersion string    `json:"max_stable_version"`
+	Downloads        int       `json:"downloads"` // all time
+	RecentDownloads  int       `json:"recent_downloads"`
+	Repository       string    `json:"repository"`
+	Updated          time.Time `json:"updated_at"`
+}
+
+func (c *Crate) toResult() (*search.PackageResult, error) {
+	u, err := url.Parse(crateURL + url.PathEscape(c.Name))
+	if err != nil {
+		return nil, err
+	}
+	r := &search.PackageResult{
+		LinkResult: search.LinkResult{URL: *u, Title: c.Name, Desc: c.Description},
+		Name:       c.Name,
+		Version:    c.MaxStableVersion,
+		Downloads:  c.RecentDownloads,
+		Published:  c.Updated.UTC(),
+	}
+	if r.Version == "" {
+		r.Version = c.MaxVersion
+	}
+	if c.Repository != "" {
+		if r.Repository, err = url.Parse(c.Repository); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+type SearchResp struct {
+	Crates []Crate `json:"crates"`
+	Meta   struct {
+		Total    int    `json:"total"`
+		NextPage string `json:"next_page"`
+	} `json:"meta"`
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("per_page", strconv.Itoa(DefaultPerPage))
+	if r.Page > 1 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	req, err := s.GetRequest(searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	// crates.io rejects requests without a user agent that identifies the client
+	req.Header.Set("User-Agent", providers.UserAgent)
+	var out SearchResp
+	if _, err := s.DoJSON(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
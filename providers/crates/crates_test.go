@@ -0,0 +1,84 @@
+package crates
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	defer func(n int) { DefaultPerPage = n }(DefaultPerPage)
+	DefaultPerPage = 2
+
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/crates_1.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/crates_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "crates.io", r.Host)
+		require.Equal(t, searchPath, r.URL.Path)
+		require.Equal(t, providers.UserAgent, r.Header.Get("User-Agent"))
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "serde"})
+	defer it.Close()
+	var (
+		got []search.Result
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+		if len(got) == 1 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []url.Values{
+		{"q": {"serde"}, "per_page": {"2"}},
+		{"q": {"serde"}, "per_page": {"2"}, "page": {"2"}},
+	}, reqs)
+	require.Len(t, got, 3)
+	require.Equal(t, &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://crates.io/crates/serde"),
+			Title: "serde",
+			Desc:  "A generic serialization/deserialization framework",
+		},
+		Name:       "serde",
+		Version:    "1.0.203",
+		Downloads:  44310257,
+		Repository: providertest.MustURL("https://github.com/serde-rs/serde"),
+		Published:  time.Date(2024, 5, 25, 17, 33, 49, 81283000, time.UTC),
+	}, got[0])
+
+	ng := got[1].(*search.PackageResult)
+	require.Equal(t, "0.10.0-rc.1", ng.Version)
+	require.Equal(t, 0, ng.Downloads) // no recent downloads
+	require.Nil(t, ng.Repository)
+	require.Equal(t, "serde_json", got[2].GetTitle())
+
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	var rest []string
+	for it.Next(ctx) {
+		rest = append(rest, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"serde_yaml_ng", "serde_json"}, rest)
+}
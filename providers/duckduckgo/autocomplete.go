@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/search"
 )
 
 const (
@@ -15,10 +16,13 @@ var (
 	_ autocomplete.Service = (*Service)(nil)
 )
 
-func (s *Service) AutoComplete(ctx context.Context, text string) ([]string, error) {
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
 	params := make(url.Values)
-	params.Set("q", text)
+	params.Set("q", req.Text)
 	params.Set("type", "json")
+	if !req.Lang.IsRoot() {
+		params.Set("kl", string(toRegion(req.Lang, search.RegionCode{})))
+	}
 
 	var list []struct {
 		Text string `json:"phrase"`
@@ -4,12 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dennwc/metasearch/autocomplete"
 	"github.com/stretchr/testify/require"
 )
 
 func TestAutoComplete(t *testing.T) {
 	s := New()
-	list, err := s.AutoComplete(context.TODO(), "sola")
+	list, err := s.AutoComplete(context.TODO(), autocomplete.Request{Text: "sola"})
 	require.NoError(t, err)
 	require.NotEmpty(t, list)
 
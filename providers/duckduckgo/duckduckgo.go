@@ -47,7 +47,7 @@ func (s *Service) Search(ctx context.Context, req search.Request) search.ResultI
 		Query: req.Query,
 	}
 	if !req.Lang.IsRoot() {
-		r.Region = toRegion(req.Lang.String())
+		r.Region = toRegion(req.Lang, req.Region)
 	}
 	return &searchIter{s: s, next: s.newSearch(r)}
 }
@@ -36,13 +36,20 @@ func (c regionCode) toLang() string {
 	return s[i+1:] + "-" + s[:i]
 }
 
-func toRegion(s string) regionCode {
-	s = strings.ToLower(s)
-	i := strings.Index(s, "-")
-	if i < 0 {
-		panic(s)
+// toRegion converts a language code to DDG region code. If the region is not set,
+// the one most likely for the language is used.
+func toRegion(lang search.LangCode, reg search.RegionCode) regionCode {
+	base, _ := lang.Base()
+	if reg == (search.RegionCode{}) {
+		reg, _ = lang.Region()
+	}
+	s := strings.ToLower(reg.String()) + "-" + base.String()
+	for code, alias := range langAliases {
+		if s == alias {
+			return regionCode(code)
+		}
 	}
-	return regionCode(s[i+1:] + "-" + s[:i])
+	return regionCode(s)
 }
 
 type region struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dennwc/metasearch/search"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,3 +38,26 @@ func TestLanguages(t *testing.T) {
 	t.Logf("%d %q", len(list), list)
 	require.True(t, len(list) >= expectedLang-1) // -"All"
 }
+
+func TestToRegion(t *testing.T) {
+	for _, c := range []struct {
+		lang   string
+		region string
+		exp    regionCode
+	}{
+		{lang: "de", exp: "de-de"},
+		{lang: "de-AT", exp: "at-de"},
+		{lang: "de", region: "CH", exp: "ch-de"},
+		{lang: "en", exp: "us-en"},
+		{lang: "ja-JP", exp: "jp-jp"},
+	} {
+		t.Run(c.lang+"-"+c.region, func(t *testing.T) {
+			var reg search.RegionCode
+			if c.region != "" {
+				reg = search.MustParseRegionCode(c.region)
+			}
+			got := toRegion(search.MustParseLangCode(c.lang), reg)
+			require.Equal(t, c.exp, got)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package providers
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvList returns values of a comma- or space-separated environment variable.
+func EnvList(name string) []string {
+	return strings.FieldsFunc(os.Getenv(name), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
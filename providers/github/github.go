@@ -0,0 +1,329 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "github"
+	baseURL  = "https://api.github.com"
+
+	reposPath  = "/search/repositories"
+	issuesPath = "/search/issues"
+
+	// EnvToken is an optional GitHub API token. Unauthenticated requests have a much lower rate limit.
+	EnvToken = "METAS_GITHUB_TOKEN"
+)
+
+// Kind of the objects to search for.
+type Kind string
+
+const (
+	Repositories = Kind("repositories")
+	Issues       = Kind("issues")
+)
+
+var (
+	DefaultPerPage = 30
+	maxDescLen     = 300
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		s := New(Repositories)
+		s.Token = os.Getenv(EnvToken)
+		return s, nil
+	})
+	providers.Register(provName+"-"+string(Issues), func(ctx context.Context) (providers.Provider, error) {
+		s := New(Issues)
+		s.Token = os.Getenv(EnvToken)
+		return s, nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+// New creates a GitHub search provider for a given kind of objects.
+func New(kind Kind) *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+		Kind:       kind,
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	Kind  Kind
+	Token string
+}
+
+func (s *Service) ID() string {
+	if s.Kind == Issues {
+		return provName + "-" + string(Issues)
+	}
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) path() string {
+	if s.Kind == Issues {
+		return issuesPath
+	}
+	return reposPath
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, Page: 1}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, more: resp.More, fetched: true, page: resp.Items, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	more    bool
+	fetched bool
+
+	page []Item
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || !it.more {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Items
+	it.more = resp.More
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page"`
+}
+
+// Item is either a repository or an issue.
+type Item struct {
+	HTMLURL string    `json:"html_url"`
+	Updated time.Time `json:"updated_at"`
+
+	// repositories
+	FullName    string   `json:"full_name"`
+	Description string   `json:"description"`
+	Stars       int      `json:"stargazers_count"`
+	Forks       int      `json:"forks_count"`
+	Language    string   `json:"language"`
+	Topics      []string `json:"topics"`
+
+	// issues
+	Title         string `json:"title"`
+	Body          string `json:"body"`
+	State         string `json:"state"`
+	Comments      int    `json:"comments"`
+	RepositoryURL string `json:"repository_url"`
+	Labels        []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"pull_request"`
+}
+
+// excerpt returns the first paragraph of the text, truncated to a given length.
+func excerpt(s string, n int) string {
+	s = strings.TrimSpace(strings.Replace(s, "\r\n", "\n", -1))
+	if i := strings.Index(s, "\n\n"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
+
+func (r *Item) toResult() (search.Result, error) {
+	u, err := url.Parse(r.HTMLURL)
+	if err != nil {
+		return nil, err
+	}
+	if r.FullName != "" {
+		return &search.RepoResult{
+			LinkResult: search.LinkResult{URL: *u, Title: r.FullName, Desc: r.Description},
+			Name:       r.FullName,
+			Stars:      r.Stars,
+			Forks:      r.Forks,
+			Language:   r.Language,
+			Topics:     r.Topics,
+			Updated:    r.Updated,
+		}, nil
+	}
+	out := &search.IssueResult{
+		LinkResult:  search.LinkResult{URL: *u, Title: r.Title, Desc: excerpt(r.Body, maxDescLen)},
+		Repo:        strings.TrimPrefix(r.RepositoryURL, baseURL+"/repos/"),
+		State:       r.State,
+		PullRequest: r.PullRequest != nil,
+		Comments:    r.Comments,
+		Updated:     r.Updated,
+	}
+	for _, l := range r.Labels {
+		out.Labels = append(out.Labels, l.Name)
+	}
+	return out, nil
+}
+
+type SearchResp struct {
+	Total      int    `json:"total_count"`
+	Incomplete bool   `json:"incomplete_results"`
+	Items      []Item `json:"items"`
+	// More is set if there is a next page.
+	More bool `json:"-"`
+}
+
+var reLink = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="([^"]+)"`)
+
+// hasNext checks if the Link header refers to the next page.
+func hasNext(h http.Header) bool {
+	for _, m := range reLink.FindAllStringSubmatch(h.Get("Link"), -1) {
+		for _, rel := range strings.Fields(m[2]) {
+			if rel == "next" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SearchRaw fetches a given page of search results.
+//
+// The URL is always built from the request, so the token is never sent to another host.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("per_page", strconv.Itoa(DefaultPerPage))
+	if r.Page > 1 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	req, err := s.GetRequest(s.path(), params)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	var out SearchResp
+	h, err := s.DoJSON(ctx, req, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.More = hasNext(h)
+	return &out, nil
+}
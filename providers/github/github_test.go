@@ -0,0 +1,173 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepos(t *testing.T) {
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/repos_1.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/repos_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "api.github.com", r.Host)
+		require.Equal(t, reposPath, r.URL.Path)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "" {
+			w.Header().Set("Link", `<https://api.github.com/search/repositories?q=go&per_page=2&page=2>; rel="next", `+
+				`<https://api.github.com/search/repositories?q=go&per_page=2&page=2>; rel="last"`)
+			page1(w, r)
+			return
+		}
+		w.Header().Set("Link", `<https://api.github.com/search/repositories?q=go&per_page=2&page=1>; rel="prev", `+
+			`<https://api.github.com/search/repositories?q=go&per_page=2&page=1>; rel="first"`)
+		page2(w, r)
+	}))
+
+	s := New(Repositories)
+	s.Token = "secret"
+	s.SetHTTPClient(cli)
+	require.Equal(t, "github", s.ID())
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "go"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []url.Values{
+		{"q": {"go"}, "per_page": {"30"}},
+		{"q": {"go"}, "per_page": {"30"}, "page": {"2"}},
+	}, reqs)
+	require.Len(t, got, 3)
+	require.Equal(t, &search.RepoResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://github.com/golang/go"),
+			Title: "golang/go",
+			Desc:  "The Go programming language",
+		},
+		Name:     "golang/go",
+		Stars:    119512,
+		Forks:    17401,
+		Language: "Go",
+		Topics:   []string{"go", "golang", "language", "programming-language"},
+		Updated:  time.Date(2024, 5, 2, 10, 12, 31, 0, time.UTC),
+	}, got[0])
+	last := got[2].(*search.RepoResult)
+	require.Equal(t, "dennwc/metasearch", last.Name)
+	require.Equal(t, "", last.Desc)
+	require.Equal(t, "", last.Language)
+}
+
+func TestContinueSearch(t *testing.T) {
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/repos_1.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/repos_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("Authorization"))
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "" {
+			w.Header().Set("Link", `<https://api.github.com/search/repositories?q=go&page=2>; rel="next"`)
+			page1(w, r)
+			return
+		}
+		page2(w, r)
+	}))
+	s := New(Repositories)
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "go"})
+	require.True(t, it.Next(ctx))
+	require.True(t, it.Next(ctx))
+	tok := it.Token()
+	require.NoError(t, it.Close())
+
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	require.True(t, it.Next(ctx))
+	require.Equal(t, "dennwc/metasearch", it.Result().GetTitle())
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 3)
+}
+
+func TestContinueForeignHost(t *testing.T) {
+	var hosts []string
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.Host)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": []}`))
+	}))
+	s := New(Repositories)
+	s.Token = "secret"
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	// tokens of older versions carried a page URL
+	it := s.ContinueSearch(ctx, search.Token(`{"url":"http://attacker.example/search/repositories?q=go"}`))
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+
+	it = s.ContinueSearch(ctx, search.Token(`{"req":{"q":"go","page":2},"url":"http://attacker.example/"}`))
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+	require.NotEmpty(t, hosts)
+	for _, h := range hosts {
+		require.Equal(t, "api.github.com", h)
+	}
+}
+
+func TestSearchIssues(t *testing.T) {
+	_, cli := providertest.NewServer(t, providertest.ServeFile(t, "testdata/issues.json", "application/json"))
+	s := New(Issues)
+	s.SetHTTPClient(cli)
+	require.Equal(t, "github-issues", s.ID())
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "range over int repo:golang/go"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []search.Result{
+		&search.IssueResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://github.com/golang/go/issues/57001"),
+				Title: "proposal: spec: add range over int",
+				Desc:  "This proposal is to allow ranging over integers.",
+			},
+			Repo:     "golang/go",
+			State:    "closed",
+			Comments: 127,
+			Labels:   []string{"Proposal", "LanguageChange"},
+			Updated:  time.Date(2024, 1, 9, 18, 20, 11, 0, time.UTC),
+		},
+		&search.IssueResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://github.com/golang/go/pull/61405"),
+				Title: "cmd/compile: implement range over int",
+			},
+			Repo:        "golang/go",
+			State:       "open",
+			PullRequest: true,
+			Comments:    3,
+			Updated:     time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}, got)
+}
@@ -0,0 +1,333 @@
+package godoc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "godoc"
+	baseURL    = "https://pkg.go.dev"
+	searchPath = "/search"
+)
+
+var (
+	DefaultLimit = 25
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+// New creates a provider for pkg.go.dev that resolves import paths using the module proxy from GOPROXY.
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+		Proxy:      NewProxy(ProxyURL()),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	// Proxy is used to resolve exact import paths. If nil, only pkg.go.dev search is used.
+	Proxy *Proxy
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// reImportPath matches queries that look like import paths, e.g. "github.com/user/repo".
+var reImportPath = regexp.MustCompile(`^[a-z0-9.-]+\.[a-z]{2,}(/[^\s/]+)+$`)
+
+// Resolve finds a package with a given import path in the module proxy.
+func (s *Service) Resolve(ctx context.Context, path string) (*search.PackageResult, error) {
+	_, info, err := s.Proxy.FindModule(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	r := docResult(path)
+	r.Version = info.Version
+	r.Published = info.Time
+	return r, nil
+}
+
+func docResult(path string) *search.PackageResult {
+	return &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   url.URL{Scheme: "https", Host: "pkg.go.dev", Path: "/" + path},
+			Title: path,
+		},
+		Name: path,
+	}
+}
+
+// resolveQuery resolves a query that looks like an import path, or returns nil.
+func (s *Service) resolveQuery(ctx context.Context, q string) *Exact {
+	if s.Proxy == nil || !reImportPath.MatchString(q) {
+		return nil
+	}
+	r, err := s.Resolve(ctx, q)
+	if err != nil {
+		return nil
+	}
+	return &Exact{Path: r.Name, Version: r.Version, Published: r.Published}
+}
+
+// withExact puts an exact match first. If the search results contain the same package,
+// it is moved to the first place instead, since it has more details.
+func withExact(exact *search.PackageResult, list []*search.PackageResult) []*search.PackageResult {
+	if exact == nil {
+		return list
+	}
+	out := []*search.PackageResult{exact}
+	for _, r := range list {
+		if r.Name == exact.Name {
+			out[0] = r
+		} else {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// without removes a package with a given name from the list.
+func without(name string, list []*search.PackageResult) []*search.PackageResult {
+	if name == "" {
+		return list
+	}
+	out := list[:0:0]
+	for _, r := range list {
+		if r.Name != name {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: strings.TrimSpace(req.Query), Page: 1}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	page, more, err := s.page(ctx, &t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, more: more, fetched: true, page: page, i: t.Off}
+}
+
+// page returns a single page of results. The exact match is resolved on the first page and stored
+// in the request, so it is never resolved again when the search continues.
+func (s *Service) page(ctx context.Context, r *SearchReq) ([]*search.PackageResult, bool, error) {
+	if r.Page <= 1 && !r.Resolved {
+		r.Exact = s.resolveQuery(ctx, r.Query)
+		r.Resolved = true
+	}
+	resp, err := s.SearchRaw(ctx, *r)
+	if err != nil {
+		return nil, false, err
+	}
+	if r.Exact == nil {
+		return resp.Results, resp.More, nil
+	}
+	if r.Page <= 1 {
+		return withExact(r.Exact.result(), resp.Results), resp.More, nil
+	}
+	return without(r.Exact.Path, resp.Results), resp.More, nil
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	more    bool
+	fetched bool
+
+	page []*search.PackageResult
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || !it.more {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	page, more, err := it.s.page(ctx, &it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.more = more
+	it.page = page
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i]
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page"`
+	// Exact is a package resolved on the first page. It is returned first and removed from the next pages.
+	Exact *Exact `json:"exact,omitempty"`
+	// Resolved is set once the query was resolved, even if there is no exact match.
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+// Exact is a package found by its import path in the module proxy.
+type Exact struct {
+	Path      string    `json:"path"`
+	Version   string    `json:"version,omitempty"`
+	Published time.Time `json:"published,omitempty"`
+}
+
+func (e *Exact) result() *search.PackageResult {
+	r := docResult(e.Path)
+	r.Version = e.Version
+	r.Published = e.Published
+	return r
+}
+
+type SearchResp struct {
+	Results []*search.PackageResult
+	// More is set if there are more pages.
+	More bool
+}
+
+// parseCount parses numbers like "3,146".
+func parseCount(s string) int {
+	n, _ := strconv.Atoi(strings.Replace(strings.TrimSpace(s), ",", "", -1))
+	return n
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("m", "package")
+	params.Set("limit", strconv.Itoa(DefaultLimit))
+	if r.Page > 1 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	doc, err := s.GetHTML(ctx, searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	out := &SearchResp{
+		More: doc.Find(`a.Pagination-next[href]`).Size() != 0,
+	}
+	doc.Find(`.SearchSnippet`).Each(func(_ int, sel *goquery.Selection) {
+		a := sel.Find(`.SearchSnippet-headerContainer a[href]`).First()
+		path := strings.Trim(a.Find(`.SearchSnippet-header-path`).Text(), " ()\n")
+		if path == "" {
+			path = strings.TrimPrefix(a.AttrOr("href", ""), "/")
+		}
+		if path == "" {
+			return
+		}
+		res := docResult(path)
+		res.Desc = strings.TrimSpace(sel.Find(`.SearchSnippet-synopsis`).Text())
+		info := sel.Find(`.SearchSnippet-infoLabel`)
+		res.Imports = parseCount(info.Find(`a[href$="tab=importedby"] strong`).Text())
+		res.License = strings.TrimSpace(info.Find(`[data-test-id="snippet-license"]`).Text())
+		info.Find(`strong`).EachWithBreak(func(_ int, v *goquery.Selection) bool {
+			if t := strings.TrimSpace(v.Text()); strings.HasPrefix(t, "v") {
+				res.Version = t
+				return false
+			}
+			return true
+		})
+		if t, err := time.Parse("Jan 2, 2006", strings.TrimSpace(info.Find(`[data-test-id="snippet-published"]`).Text())); err == nil {
+			res.Published = t
+		}
+		out.Results = append(out.Results, res)
+	})
+	return out, nil
+}
@@ -0,0 +1,147 @@
+package godoc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func testProxy(t *testing.T) *Proxy {
+	dir, err := filepath.Abs("testdata/proxy")
+	require.NoError(t, err)
+	return NewProxy("file://" + filepath.ToSlash(dir))
+}
+
+func TestProxy(t *testing.T) {
+	p := testProxy(t)
+	ctx := context.Background()
+
+	list, err := p.Versions(ctx, "github.com/PuerkitoBio/goquery")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.5.0", "v1.9.1", "v1.10.0-rc.1", "v1.10.0"}, list)
+
+	info, err := p.Latest(ctx, "github.com/PuerkitoBio/goquery")
+	require.NoError(t, err)
+	require.Equal(t, &ModuleInfo{
+		Version: "v1.10.0",
+		Time:    time.Date(2024, 9, 6, 15, 29, 44, 0, time.UTC),
+	}, info)
+
+	mod, info, err := p.FindModule(ctx, "golang.org/x/text/language/display")
+	require.NoError(t, err)
+	require.Equal(t, "golang.org/x/text", mod)
+	require.Equal(t, "v0.14.0", info.Version)
+
+	_, _, err = p.FindModule(ctx, "example.com/missing/pkg")
+	require.Error(t, err)
+}
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/search_1.html", "text/html")
+	page2 := providertest.ServeFile(t, "testdata/search_2.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "pkg.go.dev", r.Host)
+		require.Equal(t, searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.Proxy = testProxy(t)
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "github.com/PuerkitoBio/goquery"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, "package", reqs[0].Get("m"))
+	require.Equal(t, "2", reqs[1].Get("page"))
+
+	var names []string
+	for _, r := range got {
+		names = append(names, r.(*search.PackageResult).Name)
+	}
+	// exact match is resolved through the proxy and returned first
+	require.Equal(t, []string{
+		"github.com/PuerkitoBio/goquery",
+		"golang.org/x/text/language",
+		"golang.org/x/text/language/display",
+	}, names)
+	require.Equal(t, &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   url.URL{Scheme: "https", Host: "pkg.go.dev", Path: "/github.com/PuerkitoBio/goquery"},
+			Title: "github.com/PuerkitoBio/goquery",
+		},
+		Name:      "github.com/PuerkitoBio/goquery",
+		Version:   "v1.10.0",
+		Published: time.Date(2024, 9, 6, 15, 29, 44, 0, time.UTC),
+	}, got[0])
+	require.Equal(t, &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   url.URL{Scheme: "https", Host: "pkg.go.dev", Path: "/golang.org/x/text/language"},
+			Title: "golang.org/x/text/language",
+			Desc:  "Package language implements BCP 47 language tags and related functionality.",
+		},
+		Name:      "golang.org/x/text/language",
+		Version:   "v0.14.0",
+		License:   "BSD-3-Clause",
+		Imports:   8512,
+		Published: time.Date(2023, 10, 25, 0, 0, 0, 0, time.UTC),
+	}, got[1])
+}
+
+func TestContinueSearch(t *testing.T) {
+	n := 0
+	page1 := providertest.ServeFile(t, "testdata/search_1.html", "text/html")
+	page2 := providertest.ServeFile(t, "testdata/search_2.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if r.URL.Query().Get("page") == "" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.Proxy = testProxy(t)
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	// no requests are sent until the iterator is used
+	it := s.Search(ctx, search.Request{Query: "github.com/PuerkitoBio/goquery"})
+	require.Equal(t, 0, n)
+	require.False(t, it.(*searchIter).cur.Resolved)
+	require.True(t, it.Next(ctx))
+	require.True(t, it.Next(ctx))
+	require.Equal(t, "golang.org/x/text/language", it.Result().GetTitle())
+	tok := it.Token()
+	it.Close()
+
+	// the exact match is stored in the token, thus a proxy failure does not shift results
+	s.Proxy = NewProxy("file:///nonexistent")
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	var names []string
+	for it.Next(ctx) {
+		names = append(names, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"golang.org/x/text/language/display"}, names)
+}
@@ -0,0 +1,201 @@
+package godoc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+)
+
+const defaultProxy = "https://proxy.golang.org"
+
+// ProxyURL returns the first usable module proxy from the GOPROXY environment variable.
+func ProxyURL() string {
+	for _, v := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool {
+		return r == ',' || r == '|'
+	}) {
+		if v != "direct" && v != "off" {
+			return v
+		}
+	}
+	return defaultProxy
+}
+
+// NewProxy creates a client for a Go module proxy. Both HTTP(S) and file:// URLs are supported.
+func NewProxy(base string) *Proxy {
+	base = strings.TrimSuffix(base, "/")
+	if !strings.HasPrefix(base, "file://") {
+		return &Proxy{HTTPClient: providers.NewHTTPClient(base)}
+	}
+	dir := filepath.FromSlash(strings.TrimPrefix(base, "file://"))
+	p := &Proxy{HTTPClient: providers.NewHTTPClient("file://")}
+	p.SetHTTPClient(&http.Client{Transport: http.NewFileTransport(http.Dir(dir))})
+	return p
+}
+
+// Proxy is a client for the Go module proxy protocol.
+type Proxy struct {
+	providers.HTTPClient
+}
+
+// ModuleInfo is a module version info returned by the proxy.
+type ModuleInfo struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// escapePath escapes a module path by replacing upper-case letters with "!" followed by a lower-case letter.
+func escapePath(path string) string {
+	var buf strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			buf.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// Versions lists known versions of a module.
+func (p *Proxy) Versions(ctx context.Context, module string) ([]string, error) {
+	resp, err := p.Get(ctx, "/"+escapePath(module)+"/@v/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, &providers.ErrHTTPStatus{Status: resp.Status, Code: resp.StatusCode}
+	}
+	var out []string
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if v := strings.TrimSpace(sc.Text()); v != "" {
+			out = append(out, v)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return compareVersions(out[i], out[j]) < 0
+	})
+	return out, nil
+}
+
+// Info returns the info about a given module version.
+func (p *Proxy) Info(ctx context.Context, module, version string) (*ModuleInfo, error) {
+	var info ModuleInfo
+	if err := p.GetJSON(ctx, "/"+escapePath(module)+"/@v/"+escapePath(version)+".info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Latest returns the latest version of a module. If the proxy doesn't support the @latest endpoint,
+// the highest version from the list is used.
+func (p *Proxy) Latest(ctx context.Context, module string) (*ModuleInfo, error) {
+	var info ModuleInfo
+	err := p.GetJSON(ctx, "/"+escapePath(module)+"/@latest", nil, &info)
+	if err == nil {
+		return &info, nil
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+	list, lerr := p.Versions(ctx, module)
+	if lerr != nil {
+		return nil, lerr
+	} else if len(list) == 0 {
+		return nil, err
+	}
+	return p.Info(ctx, module, list[len(list)-1])
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(*providers.ErrHTTPStatus)
+	return ok && (e.Code == http.StatusNotFound || e.Code == http.StatusGone)
+}
+
+// parseVersion splits a semantic version into numeric parts and a pre-release suffix.
+func parseVersion(v string) (nums [3]int, pre string, ok bool) {
+	if !strings.HasPrefix(v, "v") {
+		return nums, "", false
+	}
+	v = v[1:]
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return nums, "", false
+	}
+	for i, s := range parts {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nums, "", false
+		}
+		nums[i] = n
+	}
+	return nums, pre, true
+}
+
+// compareVersions compares two semantic versions. Invalid versions are ordered before valid ones.
+// Pre-release suffixes are compared as strings, which is enough to order releases correctly.
+func compareVersions(a, b string) int {
+	na, pa, oka := parseVersion(a)
+	nb, pb, okb := parseVersion(b)
+	switch {
+	case !oka && !okb:
+		return strings.Compare(a, b)
+	case !oka:
+		return -1
+	case !okb:
+		return +1
+	}
+	for i := range na {
+		if na[i] != nb[i] {
+			if na[i] < nb[i] {
+				return -1
+			}
+			return +1
+		}
+	}
+	switch {
+	case pa == pb:
+		return 0
+	case pa == "":
+		return +1
+	case pb == "":
+		return -1
+	}
+	return strings.Compare(pa, pb)
+}
+
+// FindModule finds a module that provides a given package by trying the package path and its parents.
+func (p *Proxy) FindModule(ctx context.Context, pkg string) (string, *ModuleInfo, error) {
+	path := strings.Trim(pkg, "/")
+	for {
+		info, err := p.Latest(ctx, path)
+		if err == nil {
+			return path, info, nil
+		} else if !isNotFound(err) {
+			return "", nil, err
+		}
+		i := strings.LastIndexByte(path, '/')
+		if i < 0 {
+			return "", nil, fmt.Errorf("module for %q not found", pkg)
+		}
+		path = path[:i]
+	}
+}
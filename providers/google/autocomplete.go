@@ -0,0 +1,64 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"golang.org/x/text/language"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	autocompletePath = "/complete/search"
+)
+
+var (
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	r := SearchReq{Query: req.Text}
+	if !req.Lang.IsRoot() {
+		r.Language = req.Lang.String()
+	}
+	if req.Region != (search.RegionCode{}) {
+		r.Country = req.Region.String()
+	} else if reg, conf := req.Lang.Region(); conf == language.Exact {
+		r.Country = reg.String()
+	}
+	r.normalize()
+
+	hostname := defaultHostname
+	if s.UseLocalDomain {
+		if h, ok := hostnameFor(r.Country); ok {
+			hostname = h
+		}
+	}
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("client", "firefox")
+	params.Set("hl", r.Language)
+	if r.Country != "" {
+		params.Set("gl", r.Country)
+	}
+	params.Set("ie", "utf-8")
+	params.Set("oe", "utf-8")
+
+	// response is in the OpenSearch suggestions format: ["query", ["suggestion", ...]]
+	var resp []json.RawMessage
+	if err := s.GetJSON(ctx, "https://"+hostname+autocompletePath, params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("unexpected autocomplete response")
+	}
+	var list []string
+	if err := json.Unmarshal(resp[1], &list); err != nil {
+		return nil, fmt.Errorf("cannot parse autocomplete response: %v", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,65 @@
+package google
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoComplete(t *testing.T) {
+	var last *http.Request
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		require.Equal(t, autocompletePath, r.URL.Path)
+		require.Equal(t, "firefox", r.URL.Query().Get("client"))
+		w.Header().Set("Content-Type", "text/javascript; charset=UTF-8")
+		w.Write([]byte(`["sola",["solar","solaris","solarium","solar eclipse 2024"],[],{"google:suggestsubtypes":[[512],[512],[512],[512]]}]`))
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	s.UseLocalDomain = true
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		name string
+		req  autocomplete.Request
+		host string
+		hl   string
+		gl   string
+	}{
+		{name: "default", host: defaultHostname, hl: "en"},
+		{
+			name: "lang",
+			req:  autocomplete.Request{Lang: search.MustParseLangCode("de")},
+			host: defaultHostname, hl: "de",
+		},
+		{
+			name: "lang region",
+			req:  autocomplete.Request{Lang: search.MustParseLangCode("de-AT")},
+			host: "www.google.at", hl: "de-AT", gl: "AT",
+		},
+		{
+			name: "region",
+			req:  autocomplete.Request{Region: search.MustParseRegionCode("JP")},
+			host: "www.google.co.jp", hl: "ja", gl: "JP",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			c.req.Text = "sola"
+			list, err := s.AutoComplete(ctx, c.req)
+			require.NoError(t, err)
+			require.Equal(t, []string{"solar", "solaris", "solarium", "solar eclipse 2024"}, list)
+
+			require.Equal(t, c.host, last.Host)
+			q := last.URL.Query()
+			require.Equal(t, "sola", q.Get("q"))
+			require.Equal(t, c.hl, q.Get("hl"))
+			require.Equal(t, c.gl, q.Get("gl"))
+		})
+	}
+}
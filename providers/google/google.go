@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 
@@ -34,14 +35,20 @@ func init() {
 
 func New() *Service {
 	return &Service{
-		HTTPClient: providers.NewHTTPClient(""),
+		HTTPClient:     providers.NewHTTPClient(""),
+		UseLocalDomain: true,
 	}
 }
 
 type Service struct {
 	providers.HTTPClient
 
+	// UseLocalDomain sends requests for a region to its Google domain, e.g. www.google.de for DE.
+	// It is enabled by New.
 	UseLocalDomain bool
+
+	mu      sync.RWMutex
+	regions []search.Region // cached by Regions
 }
 
 func (*Service) ID() string {
@@ -178,13 +185,22 @@ type SearchResp struct {
 	Results []Result
 }
 
-func (s *Service) searchPage(ctx context.Context, r SearchReq) (io.ReadCloser, string, error) {
+// normalize fills the language from the country, if only the country is set.
+func (r *SearchReq) normalize() {
+	r.Country = strings.ToUpper(r.Country)
+	if r.Language == "" && r.Country != "" {
+		r.Language, _ = langForCountry(r.Country)
+	}
 	if r.Language == "" {
 		r.Language = defaultLanguage
 	}
+}
+
+func (s *Service) searchPage(ctx context.Context, r SearchReq) (io.ReadCloser, string, error) {
+	r.normalize()
 	hostname := defaultHostname
-	if !s.UseLocalDomain {
-		if h, ok := countryHostname[r.Country]; ok {
+	if s.UseLocalDomain {
+		if h, ok := hostnameFor(r.Country); ok {
 			hostname = h
 		}
 	}
@@ -197,8 +213,7 @@ func (s *Service) searchPage(ctx context.Context, r SearchReq) (io.ReadCloser, s
 	params.Set("hl", r.Language)
 	params.Set("ei", "x")
 	if r.Country != "" {
-		// TODO: derive country from the language?
-		params.Set("cr", "country"+strings.ToUpper(r.Country))
+		params.Set("cr", "country"+r.Country)
 	}
 	if r.SafeSearch {
 		params.Set("safe", "active")
@@ -209,7 +224,11 @@ func (s *Service) searchPage(ctx context.Context, r SearchReq) (io.ReadCloser, s
 	if err != nil {
 		return nil, "", err
 	}
-	req.Header.Set("Accept-Language", r.Language+","+r.Language+"-"+r.Country)
+	if r.Country != "" {
+		req.Header.Set("Accept-Language", r.Language+"-"+r.Country+","+r.Language)
+	} else {
+		req.Header.Set("Accept-Language", r.Language)
+	}
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.AddCookie(&http.Cookie{
 		Name: "GOOGLE_ABUSE_EXEMPTION", Value: "x",
@@ -2,9 +2,11 @@ package google
 
 import (
 	"context"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/dennwc/metasearch/providers/providertest"
 	"github.com/dennwc/metasearch/search"
 	"github.com/dennwc/metasearch/search/searchtest"
 	"github.com/stretchr/testify/require"
@@ -19,7 +21,7 @@ func TestSearchRaw(t *testing.T) {
 	}
 	resp, err := s.SearchRaw(ctx, req)
 	require.NoError(t, err)
-	t.Logf("%d %q", len(resp.Results), resp)
+	t.Logf("%d %v", len(resp.Results), resp)
 	require.True(t, len(resp.Results) > 2)
 	require.True(t, resp.Total >= 1000000000, "%d", resp.Total)
 
@@ -30,7 +32,7 @@ func TestSearchRaw(t *testing.T) {
 	req.Offset += len(resp.Results)
 	resp, err = s.SearchRaw(ctx, req)
 	require.NoError(t, err)
-	t.Logf("%d %q", len(resp.Results), resp)
+	t.Logf("%d %v", len(resp.Results), resp)
 	require.True(t, len(resp.Results) > 2)
 
 	r2 := resp.Results[0]
@@ -58,3 +60,71 @@ func TestGoogle(t *testing.T) {
 		Safe: true,
 	})
 }
+
+func TestSearchRegion(t *testing.T) {
+	var last *http.Request
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		w.Write([]byte(`<html><body></body></html>`))
+	}))
+	// regional domains are used by default
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		name   string
+		req    search.Request
+		host   string
+		lang   string
+		cr     string
+		accept string
+	}{
+		{
+			name: "default",
+			host: defaultHostname, lang: "en", accept: "en",
+		},
+		{
+			name: "lang",
+			req:  search.Request{Lang: search.MustParseLangCode("de")},
+			host: defaultHostname, lang: "de", accept: "de",
+		},
+		{
+			name: "region",
+			req:  search.Request{Region: search.MustParseRegionCode("UA")},
+			host: "www.google.com.ua", lang: "uk", cr: "countryUA", accept: "uk-UA,uk",
+		},
+		{
+			name: "both",
+			req: search.Request{
+				Lang:   search.MustParseLangCode("fr"),
+				Region: search.MustParseRegionCode("CH"),
+			},
+			host: "www.google.ch", lang: "fr", cr: "countryCH", accept: "fr-CH,fr",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			c.req.Query = "solar"
+			it := s.Search(ctx, c.req)
+			defer it.Close()
+			require.False(t, it.Next(ctx))
+			require.NoError(t, it.Err())
+
+			require.NotNil(t, last)
+			require.Equal(t, c.host, last.Host)
+			q := last.URL.Query()
+			require.Equal(t, c.lang, q.Get("hl"))
+			require.Equal(t, "lang_"+c.lang, q.Get("lr"))
+			require.Equal(t, c.cr, q.Get("cr"))
+			require.Equal(t, c.accept, last.Header.Get("Accept-Language"))
+		})
+	}
+
+	s.UseLocalDomain = false
+	it := s.Search(ctx, search.Request{Query: "solar", Region: search.MustParseRegionCode("UA")})
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+	require.Equal(t, defaultHostname, last.Host)
+	require.Equal(t, "countryUA", last.URL.Query().Get("cr"))
+}
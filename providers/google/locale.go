@@ -3,9 +3,14 @@ package google
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
 	"github.com/dennwc/metasearch/search"
 )
 
@@ -13,6 +18,7 @@ const (
 	defaultCountry  = "US"
 	defaultLanguage = "en"
 	languagesURL    = "https://" + defaultHostname + "/preferences?#languages"
+	regionsURL      = "https://" + defaultHostname + "/preferences?#regions"
 )
 
 var (
@@ -107,6 +113,92 @@ func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
 	return out, nil
 }
 
+// hostnameFor returns a regional Google hostname for a given country code.
+func hostnameFor(country string) (string, bool) {
+	h, ok := countryHostname[strings.ToUpper(country)]
+	return h, ok
+}
+
+// langForCountry returns the most likely language for a given country code.
+func langForCountry(country string) (string, bool) {
+	tag, err := language.Parse("und-" + country)
+	if err != nil {
+		return "", false
+	}
+	base, conf := tag.Base()
+	if conf == language.No {
+		return "", false
+	}
+	return base.String(), true
+}
+
+func (s *Service) fetchRegions(ctx context.Context) ([]search.Region, error) {
+	doc, err := s.GetHTML(ctx, regionsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []search.Region
+	doc.Find(`[data-value][data-name]`).Each(func(_ int, sel *goquery.Selection) {
+		code := sel.AttrOr("data-value", "")
+		if len(code) != 2 {
+			return
+		}
+		r, err := search.ParseRegionCode(code)
+		if err != nil {
+			return
+		}
+		out = append(out, search.Region{
+			Code: r, Name: sel.AttrOr("data-name", ""),
+		})
+	})
+	if len(out) == 0 {
+		return nil, fmt.Errorf("cannot parse regions list")
+	}
+	return out, nil
+}
+
+// Regions returns the list of countries supported by Google.
+//
+// Countries with a regional hostname are always included; the rest is loaded from the preferences page,
+// if it's available. The list is cached once the preferences page is loaded.
 func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
-	return nil, nil
+	s.mu.RLock()
+	cached := s.regions
+	s.mu.RUnlock()
+	if cached != nil {
+		return append([]search.Region(nil), cached...), nil
+	}
+	names := display.English.Regions()
+	seen := make(map[search.RegionCode]struct{})
+	var out []search.Region
+	add := func(r search.Region) {
+		if _, ok := seen[r.Code]; ok {
+			return
+		}
+		seen[r.Code] = struct{}{}
+		if r.Name == "" {
+			r.Name = names.Name(r.Code)
+		}
+		out = append(out, r)
+	}
+	add(search.Region{Code: search.MustParseRegionCode(defaultCountry)})
+	for code := range countryHostname {
+		add(search.Region{Code: search.MustParseRegionCode(code)})
+	}
+	list, err := s.fetchRegions(ctx)
+	if err != nil {
+		log.Printf("google: cannot load regions: %v", err)
+	}
+	for _, r := range list {
+		add(r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Code.String() < out[j].Code.String()
+	})
+	if err == nil {
+		s.mu.Lock()
+		s.regions = out
+		s.mu.Unlock()
+	}
+	return append([]search.Region(nil), out...), nil
 }
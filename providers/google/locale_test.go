@@ -2,8 +2,10 @@ package google
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
+	"github.com/dennwc/metasearch/providers/providertest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +17,34 @@ func TestLanguages(t *testing.T) {
 	t.Logf("%d %q", len(list), list)
 	require.True(t, len(list) >= 140)
 }
+
+func TestRegions(t *testing.T) {
+	n := 0
+	prefs := providertest.ServeFile(t, "testdata/preferences.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		prefs(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	list, err := s.Regions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, len(countryHostname)+4, len(list)) // +US, AF, IS, ZW
+
+	byCode := make(map[string]string)
+	for _, r := range list {
+		byCode[r.Code.String()] = r.Name
+	}
+	require.Equal(t, "United States", byCode["US"])
+	require.Equal(t, "Germany", byCode["DE"])
+	require.Equal(t, "Zimbabwe", byCode["ZW"])
+	require.Equal(t, "Ukraine", byCode["UA"])
+
+	// the list is cached
+	list2, err := s.Regions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, list, list2)
+	require.Equal(t, 1, n)
+}
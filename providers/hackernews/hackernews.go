@@ -0,0 +1,286 @@
+package hackernews
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "hackernews"
+	forumName  = "Hacker News"
+	baseURL    = "https://hn.algolia.com/api/v1"
+	searchPath = "/search"
+	itemURL    = "https://news.ycombinator.com/item?id="
+)
+
+var (
+	DefaultHitsPerPage = 30
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var (
+	_ search.Service          = (*Service)(nil)
+	_ search.DiscussionFinder = (*Service)(nil)
+)
+
+// New creates a Hacker News provider backed by the Algolia HN Search API.
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, pages: resp.Pages, fetched: true, page: resp.Hits, i: t.Off}
+}
+
+// Discussions returns stories that link to a given URL.
+func (s *Service) Discussions(ctx context.Context, u *url.URL) ([]*search.DiscussionResult, error) {
+	resp, err := s.SearchRaw(ctx, SearchReq{Query: u.String(), URL: true})
+	if err != nil {
+		return nil, err
+	}
+	var out []*search.DiscussionResult
+	for _, h := range resp.Hits {
+		r, err := h.toResult()
+		if err != nil {
+			return nil, err
+		}
+		// the search is fuzzy, so it may return links to other pages on the same site
+		if r.Link == nil || normURL(r.Link) != normURL(u) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// normURL normalizes a URL for comparison, ignoring the scheme, "www." prefix and trailing slashes.
+func normURL(u *url.URL) string {
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	s := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		s += "?" + u.RawQuery
+	}
+	return s
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	pages   int
+	fetched bool
+
+	page []Hit
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Page+1 >= it.pages {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Hits
+	it.pages = resp.Pages
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page,omitempty"`
+	// URL restricts the search to story URLs.
+	URL bool `json:"url,omitempty"`
+}
+
+// Hit is a single story returned by the search API.
+type Hit struct {
+	ID          string `json:"objectID"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Author      string `json:"author"`
+	Points      int    `json:"points"`
+	NumComments int    `json:"num_comments"`
+	CreatedAt   int64  `json:"created_at_i"`
+	StoryText   string `json:"story_text"`
+}
+
+// plainText strips HTML tags from the story text.
+func plainText(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "<") {
+		return html.UnescapeString(s)
+	}
+	// paragraphs are separated by <p> without closing tags
+	s = strings.Replace(s, "<p>", " <p>", -1)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+func (h *Hit) toResult() (*search.DiscussionResult, error) {
+	u, err := url.Parse(itemURL + h.ID)
+	if err != nil {
+		return nil, err
+	}
+	r := &search.DiscussionResult{
+		LinkResult: search.LinkResult{URL: *u, Title: h.Title},
+		Forum:      forumName,
+		Author:     h.Author,
+		Points:     h.Points,
+		Comments:   h.NumComments,
+		Created:    time.Unix(h.CreatedAt, 0).UTC(),
+	}
+	if h.URL != "" {
+		if r.Link, err = url.Parse(h.URL); err != nil {
+			return nil, err
+		}
+		r.Desc = h.URL
+	} else {
+		r.Desc = plainText(h.StoryText)
+	}
+	return r, nil
+}
+
+type SearchResp struct {
+	Hits  []Hit `json:"hits"`
+	Total int   `json:"nbHits"`
+	Page  int   `json:"page"`
+	Pages int   `json:"nbPages"`
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("query", r.Query)
+	params.Set("tags", "story")
+	params.Set("hitsPerPage", strconv.Itoa(DefaultHitsPerPage))
+	if r.Page > 0 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.URL {
+		params.Set("restrictSearchableAttributes", "url")
+	}
+	var out SearchResp
+	if err := s.GetJSON(ctx, searchPath, params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
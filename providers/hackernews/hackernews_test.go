@@ -0,0 +1,80 @@
+package hackernews
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page0 := providertest.ServeFile(t, "testdata/search_0.json", "application/json")
+	page1 := providertest.ServeFile(t, "testdata/search_1.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "hn.algolia.com", r.Host)
+		require.Equal(t, "/api/v1"+searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "" {
+			page0(w, r)
+		} else {
+			page1(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "go"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, "story", reqs[0].Get("tags"))
+	require.Equal(t, "1", reqs[1].Get("page"))
+
+	require.Len(t, got, 3)
+	require.Equal(t, &search.DiscussionResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://news.ycombinator.com/item?id=37049990"),
+			Title: "Go 1.21 is released",
+			Desc:  "https://go.dev/blog/go1.21",
+		},
+		Link:     providertest.MustURL("https://go.dev/blog/go1.21"),
+		Forum:    "Hacker News",
+		Author:   "gopher1",
+		Points:   412,
+		Comments: 188,
+		Created:  time.Date(2023, 8, 8, 15, 1, 32, 0, time.UTC),
+	}, got[0])
+	ask := got[1].(*search.DiscussionResult)
+	require.Nil(t, ask.Link)
+	require.Equal(t, "I've been writing Python for years. Is Go worth it?", ask.Desc)
+}
+
+func TestDiscussions(t *testing.T) {
+	serve := providertest.ServeFile(t, "testdata/url.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "https://go.dev/blog/go1.21", q.Get("query"))
+		require.Equal(t, "url", q.Get("restrictSearchableAttributes"))
+		serve(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+
+	got, err := s.Discussions(context.Background(), providertest.MustURL("https://go.dev/blog/go1.21"))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "https://news.ycombinator.com/item?id=37049990", got[0].GetURL().String())
+	require.Equal(t, "https://news.ycombinator.com/item?id=37050001", got[1].GetURL().String())
+}
@@ -26,6 +26,9 @@ func (e *ErrHTTPStatus) Error() string {
 
 var debugHTTP = os.Getenv("METAS_DEBUG_HTTP") == "true"
 
+// UserAgent identifies the client for APIs that block generic user agents.
+const UserAgent = "metasearch/0.1 (+https://github.com/dennwc/metasearch)"
+
 func NewHTTPClient(base string) HTTPClient {
 	return HTTPClient{
 		cli:  http.DefaultClient,
@@ -127,10 +130,25 @@ func (c *HTTPClient) postEnc(ctx context.Context, path string, params url.Values
 }
 
 func (c *HTTPClient) GetJSON(ctx context.Context, path string, params url.Values, dst interface{}) error {
-	resp, err := c.getEnc(ctx, path, params, "application/json")
+	req, err := c.GetRequest(path, params)
 	if err != nil {
 		return err
 	}
+	_, err = c.DoJSON(ctx, req, dst)
+	return err
+}
+
+// DoJSON sends the request and decodes a JSON response into dst. It returns response headers.
+// The Accept header is set to JSON, unless the request sets it already.
+func (c *HTTPClient) DoJSON(ctx context.Context, req *http.Request, dst interface{}) (http.Header, error) {
+	accept := ""
+	if req.Header.Get("Accept") == "" {
+		accept = "application/json"
+	}
+	resp, err := c.doEnc(ctx, req, accept)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	var r io.Reader = resp.Body
@@ -140,9 +158,9 @@ func (c *HTTPClient) GetJSON(ctx context.Context, path string, params url.Values
 
 	dec := json.NewDecoder(r)
 	if err := dec.Decode(dst); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return resp.Header, nil
 }
 
 func (c *HTTPClient) GetXML(ctx context.Context, path string, params url.Values, dst interface{}) error {
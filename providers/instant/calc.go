@@ -0,0 +1,289 @@
+package instant
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// functions available in expressions.
+var functions = map[string]func(float64) float64{
+	"sqrt":  math.Sqrt,
+	"cbrt":  math.Cbrt,
+	"abs":   math.Abs,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+	"exp":   math.Exp,
+	"ln":    math.Log,
+	"log":   math.Log10,
+	"log2":  math.Log2,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+}
+
+// constants available in expressions.
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"π":  math.Pi,
+	"e":  math.E,
+}
+
+var errSyntax = errors.New("syntax error")
+
+// parser is a recursive descent parser and evaluator of arithmetic expressions:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = unary { ("*" | "/" | "%") unary | primary }
+//	unary  = ("-" | "+") unary | power
+//	power  = primary [ "^" unary ]
+//	primary = number | constant | function primary | "(" expr ")"
+type parser struct {
+	s   string
+	pos int
+	ops int // number of operators and functions, to distinguish expressions from plain numbers
+}
+
+// Eval evaluates an arithmetic expression.
+func Eval(s string) (float64, error) {
+	v, _, err := eval(s)
+	return v, err
+}
+
+// eval evaluates the expression and also returns the number of operators in it.
+func eval(s string) (float64, int, error) {
+	p := &parser{s: s}
+	v, err := p.expr()
+	if err != nil {
+		return 0, 0, err
+	}
+	p.skip()
+	if p.pos != len(p.s) {
+		return 0, 0, fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, 0, errors.New("result is not a number")
+	}
+	return v, p.ops, nil
+}
+
+func (p *parser) skip() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space character, or 0 at the end of the input.
+func (p *parser) peek() byte {
+	p.skip()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch op := p.peek(); op {
+		case '+', '-':
+			p.pos++
+			p.ops++
+			r, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			if op == '+' {
+				v += r
+			} else {
+				v -= r
+			}
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *parser) term() (float64, error) {
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		switch op {
+		case '*', '/', '%', 'x':
+			if op == 'x' && !p.isTimes() {
+				return v, nil
+			}
+			p.pos++
+			p.ops++
+			r, err := p.unary()
+			if err != nil {
+				return 0, err
+			}
+			switch op {
+			case '*', 'x':
+				v *= r
+			case '/':
+				v /= r
+			case '%':
+				v = math.Mod(v, r)
+			}
+		case '(':
+			// implicit multiplication, e.g. "2(3+4)"
+			p.ops++
+			r, err := p.primary()
+			if err != nil {
+				return 0, err
+			}
+			v *= r
+		default:
+			return v, nil
+		}
+	}
+}
+
+// isTimes checks if "x" at the current position is used as a multiplication sign, e.g. "3 x 4".
+func (p *parser) isTimes() bool {
+	rest := p.s[p.pos+1:]
+	return rest != "" && (rest[0] == ' ' || rest[0] == '(' || rest[0] == '.' || (rest[0] >= '0' && rest[0] <= '9'))
+}
+
+func (p *parser) unary() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.unary()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (float64, error) {
+	v, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() == '^' || strings.HasPrefix(p.s[p.pos:], "**") {
+		if p.s[p.pos] == '^' {
+			p.pos++
+		} else {
+			p.pos += 2
+		}
+		p.ops++
+		// right-associative, e.g. 2^3^2 = 2^9
+		r, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		v = math.Pow(v, r)
+	}
+	return v, nil
+}
+
+func (p *parser) primary() (float64, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return 0, errSyntax
+	case c == '(':
+		p.pos++
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	}
+	// identifiers: constants and functions
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !unicode.IsLetter(r) && !(p.pos > start && unicode.IsDigit(r)) {
+			break
+		}
+		p.pos += size
+	}
+	name := strings.ToLower(p.s[start:p.pos])
+	if name == "" {
+		return 0, fmt.Errorf("unexpected %q", p.s[start:])
+	}
+	if fnc, ok := functions[name]; ok {
+		p.ops++
+		v, err := p.power()
+		if err != nil {
+			return 0, err
+		}
+		return fnc(v), nil
+	}
+	if v, ok := constants[name]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown name: %q", name)
+}
+
+func (p *parser) number() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if isDigit(c) || c == '.' || c == '_' || (c == ',' && isGroup(p.s[p.pos+1:])) {
+			p.pos++
+		} else if (c == 'e' || c == 'E') && p.pos+1 < len(p.s) && (isDigit(p.s[p.pos+1]) ||
+			((p.s[p.pos+1] == '-' || p.s[p.pos+1] == '+') && p.pos+2 < len(p.s) && isDigit(p.s[p.pos+2]))) {
+			p.pos += 2
+		} else {
+			break
+		}
+	}
+	return parseNumber(p.s[start:p.pos])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isGroup checks if the string starts with a group of 3 digits that follows a thousands separator.
+func isGroup(s string) bool {
+	return len(s) >= 3 && isDigit(s[0]) && isDigit(s[1]) && isDigit(s[2]) && (len(s) == 3 || !isDigit(s[3]))
+}
+
+// parseNumber parses a decimal number, allowing digit group separators, e.g. "1,000,000" or "1_000".
+func parseNumber(s string) (float64, error) {
+	s = strings.NewReplacer(",", "", "_", "").Replace(s)
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatNumber formats the number with up to 12 significant digits.
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(v, 'g', 12, 64)
+	if strings.ContainsAny(s, "e") {
+		return s
+	}
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package instant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	cases := []struct {
+		expr string
+		v    float64
+		ops  int
+	}{
+		{expr: "42", v: 42},
+		{expr: "1,000,000", v: 1e6},
+		{expr: "1_000", v: 1000},
+		{expr: "1.5e3", v: 1500},
+		{expr: "3*(4+5)", v: 27, ops: 2},
+		{expr: "2 + 3 * 4", v: 14, ops: 2},
+		{expr: "10 - 4 - 3", v: 3, ops: 2},
+		{expr: "7 % 4", v: 3, ops: 1},
+		{expr: "2^3^2", v: 512, ops: 2},
+		{expr: "2**10", v: 1024, ops: 1},
+		{expr: "-2^2", v: -4, ops: 1},
+		{expr: "2(3+4)", v: 14, ops: 2},
+		{expr: "3 x 4", v: 12, ops: 1},
+		{expr: "sqrt 16 + 1", v: 5, ops: 2},
+		{expr: "sqrt(2)^2", v: 2, ops: 2},
+		{expr: "2 * pi", v: 2 * math.Pi, ops: 1},
+		{expr: "π", v: math.Pi},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			v, ops, err := eval(c.expr)
+			require.NoError(t, err)
+			require.InDelta(t, c.v, v, 1e-9)
+			require.Equal(t, c.ops, ops)
+		})
+	}
+	for _, expr := range []string{"", "2 +", "(1", "foo", "2x", "1/0", "1,5"} {
+		_, _, err := eval(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	for v, exp := range map[float64]string{
+		27:           "27",
+		-4:           "-4",
+		0.1 + 0.2:    "0.3",
+		1.0 / 3:      "0.333333333333",
+		3.6576:       "3.6576",
+		1e20:         "1e+20",
+		123456789012: "123456789012",
+	} {
+		require.Equal(t, exp, formatNumber(v))
+	}
+}
@@ -0,0 +1,149 @@
+package instant
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// encodings maps names of encodings to their canonical names.
+var encodings = map[string]string{
+	"hex":         "hex",
+	"hexadecimal": "hex",
+	"base16":      "hex",
+	"bin":         "binary",
+	"binary":      "binary",
+	"base2":       "binary",
+	"oct":         "octal",
+	"octal":       "octal",
+	"base8":       "octal",
+	"dec":         "decimal",
+	"decimal":     "decimal",
+	"base10":      "decimal",
+	"base64":      "base64",
+	"b64":         "base64",
+}
+
+// formatInt formats an integer in a given base with a Go-style prefix, e.g. "0xff".
+func formatInt(v int64, enc string) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign, u = "-", uint64(-v)
+	}
+	switch enc {
+	case "hex":
+		return sign + "0x" + strconv.FormatUint(u, 16)
+	case "binary":
+		return sign + "0b" + strconv.FormatUint(u, 2)
+	case "octal":
+		return sign + "0o" + strconv.FormatUint(u, 8)
+	}
+	return sign + strconv.FormatUint(u, 10)
+}
+
+// unquote removes quotes around the text, if any.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// isPrintable checks if decoded data is a readable text.
+func isPrintable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBase64 decodes both the standard and URL-safe base64, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	var last error
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		data, err := enc.DecodeString(s)
+		if err == nil {
+			return data, nil
+		}
+		last = err
+	}
+	return nil, last
+}
+
+// convertEncoding answers queries like "255 in hex", "0b1010 to decimal", "encode hello to base64"
+// or "aGVsbG8= from base64".
+func convertEncoding(q string) *answer {
+	if i := strings.LastIndex(strings.ToLower(q), " from "); i > 0 {
+		enc := encodings[strings.ToLower(strings.TrimSpace(q[i+6:]))]
+		text := unquote(strings.TrimSpace(q[:i]))
+		var (
+			data []byte
+			err  error
+		)
+		switch enc {
+		case "base64":
+			data, err = decodeBase64(text)
+		case "hex":
+			data, err = hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(text, "0x"), " ", ""))
+		default:
+			return nil
+		}
+		if err != nil || !isPrintable(data) {
+			return nil
+		}
+		return &answer{
+			Kind:     KindEncoding,
+			Question: strconv.Quote(text) + " from " + enc,
+			Value:    string(data),
+		}
+	}
+	from, to, ok := splitConv(q)
+	if !ok {
+		return nil
+	}
+	enc, ok := encodings[strings.ToLower(to)]
+	if !ok {
+		return nil
+	}
+	if v, err := strconv.ParseInt(from, 0, 64); err == nil && enc != "base64" {
+		return &answer{
+			Kind:     KindEncoding,
+			Question: from,
+			Value:    formatInt(v, enc),
+		}
+	}
+	// queries like "rgb to hex" or "jpg to base64" are not about encoding the text,
+	// thus the text must be quoted or the query must ask to encode it explicitly
+	text := unquote(from)
+	if text == from {
+		if len(from) < 7 || !strings.EqualFold(from[:7], "encode ") {
+			return nil
+		}
+		text = unquote(strings.TrimSpace(from[7:]))
+	}
+	var val string
+	switch enc {
+	case "hex":
+		val = hex.EncodeToString([]byte(text))
+	case "base64":
+		val = base64.StdEncoding.EncodeToString([]byte(text))
+	default:
+		return nil
+	}
+	return &answer{
+		Kind:     KindEncoding,
+		Question: enc + "(" + strconv.Quote(text) + ")",
+		Value:    val,
+	}
+}
@@ -0,0 +1,235 @@
+package instant
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const provName = "instant"
+
+// Kinds of answers.
+const (
+	KindCalc     = "calc"
+	KindUnit     = "unit"
+	KindTime     = "time"
+	KindEncoding = "encoding"
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ search.AnswerService = (*Service)(nil)
+)
+
+// New creates a provider that answers calculations, unit, time zone and encoding conversions locally.
+func New() *Service {
+	return &Service{Now: time.Now}
+}
+
+type Service struct {
+	// Now returns the current time. It is used for time zone conversions.
+	Now func() time.Time
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// answer is an answer together with the question it answers.
+type answer struct {
+	Kind     string
+	Question string
+	Value    string
+}
+
+func (a *answer) toResult() *search.AnswerResult {
+	return &search.AnswerResult{
+		LinkResult: search.LinkResult{Title: a.Question + " = " + a.Value},
+		Kind:       a.Kind,
+		Value:      a.Value,
+	}
+}
+
+// calculate answers arithmetic expressions, e.g. "3*(4+5)". Plain numbers are not answered.
+func calculate(q string) *answer {
+	q = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(q), "="))
+	if isDashed(q) {
+		return nil
+	}
+	v, ops, err := eval(q)
+	if err != nil || ops == 0 {
+		return nil
+	}
+	return &answer{Kind: KindCalc, Question: q, Value: formatNumber(v)}
+}
+
+// isDashed checks if the text is a sequence of digit groups separated by dashes, like "1-800-555-1234" or "2019-2020".
+// Such queries are phone numbers, ranges or dates, not subtractions.
+func isDashed(s string) bool {
+	groups := strings.Split(s, "-")
+	if len(groups) < 2 {
+		return false
+	}
+	for _, g := range groups {
+		if g == "" {
+			return false
+		}
+		for _, r := range g {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Answer answers the query, if possible. It returns nil if the query is not recognized.
+func (s *Service) Answer(ctx context.Context, req search.Request) (*search.AnswerResult, error) {
+	q := strings.TrimSpace(req.Query)
+	if q == "" {
+		return nil, nil
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	if a := answerTime(q, now()); a != nil {
+		return a.toResult(), nil
+	}
+	if a := convertUnits(q); a != nil {
+		return a.toResult(), nil
+	}
+	if a := convertEncoding(q); a != nil {
+		return a.toResult(), nil
+	}
+	if a := calculate(q); a != nil {
+		return a.toResult(), nil
+	}
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, req: req, i: -1}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	req := search.Request{Query: t.Query}
+	it := &searchIter{s: s, req: req, fetched: true, i: t.Off}
+	if err := it.fetch(ctx); err != nil {
+		return &searchIter{err: err}
+	}
+	return it
+}
+
+// searchIter returns at most one result, the answer to the query.
+type searchIter struct {
+	s       *Service
+	req     search.Request
+	fetched bool
+
+	page []*search.AnswerResult
+	i    int
+	err  error
+}
+
+func (it *searchIter) fetch(ctx context.Context) error {
+	r, err := it.s.Answer(ctx, it.req)
+	if err != nil {
+		return err
+	}
+	it.page = nil
+	if r != nil {
+		it.page = []*search.AnswerResult{r}
+	}
+	return nil
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil || it.fetched {
+		it.page = nil
+		return false
+	}
+	if err := it.fetch(ctx); err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i]
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Query: it.req.Query,
+		Off:   it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Query string `json:"q"`
+	Off   int    `json:"off,omitempty"`
+}
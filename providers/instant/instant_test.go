@@ -0,0 +1,112 @@
+package instant
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+// now is a fixed time used in tests, CET is in effect in Europe.
+var now = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func newTestService() *Service {
+	s := New()
+	s.Now = func() time.Time { return now }
+	return s
+}
+
+func TestAnswer(t *testing.T) {
+	s := newTestService()
+	cases := []struct {
+		query string
+		kind  string
+		title string
+	}{
+		{"3*(4+5)", KindCalc, "3*(4+5) = 27"},
+		{"2^10 =", KindCalc, "2^10 = 1024"},
+		{"0.1 + 0.2", KindCalc, "0.1 + 0.2 = 0.3"},
+		{"2020 - 2019", KindCalc, "2020 - 2019 = 1"},
+
+		{"12 ft in m", KindUnit, "12 ft = 3.6576 m"},
+		{"12 in in cm", KindUnit, "12 in = 30.48 cm"},
+		{"1.5kg to lbs", KindUnit, "1.5 kg = 3.30693393277 lb"},
+		{"100 °F to °C", KindUnit, "100 °F = 37.7777777778 °C"},
+		{"0 celsius in fahrenheit", KindUnit, "0 °C = 32 °F"},
+		{"2 GiB as MB", KindUnit, "2 GiB = 2147.483648 MB"},
+		{"10 sq ft in m2", KindUnit, "10 ft² = 0.9290304 m²"},
+		{"90 minutes in hours", KindUnit, "90 min = 1.5 h"},
+		{"100 km/h in mph", KindUnit, "100 km/h = 62.1371192237 mph"},
+		{"2 gallons to liters", KindUnit, "2 gal = 7.570823568 l"},
+
+		{"10am PST in Berlin", KindTime, "10:00 PST = 19:00 CET"},
+		{"15:30 UTC to Tokyo", KindTime, "15:30 UTC = 00:30 JST (+1 day)"},
+		{"1 am Europe/Berlin to new york", KindTime, "01:00 CET = 19:00 EST (-1 day)"},
+		{"12:00 utc+5:30 in utc", KindTime, "12:00 UTC+5:30 = 06:30 UTC"},
+		{"time in Tokyo", KindTime, "Time in Tokyo = 21:00 JST, Mon Jan 15"},
+		{"What time is it in London?", KindTime, "Time in London = 12:00 GMT, Mon Jan 15"},
+
+		{"255 in hex", KindEncoding, "255 = 0xff"},
+		{"0xff to decimal", KindEncoding, "0xff = 255"},
+		{"0b1010 in octal", KindEncoding, "0b1010 = 0o12"},
+		{"-10 to binary", KindEncoding, "-10 = -0b1010"},
+		{"encode hello to base64", KindEncoding, `base64("hello") = aGVsbG8=`},
+		{`'hello' to base64`, KindEncoding, `base64("hello") = aGVsbG8=`},
+		{`"hi there" in hex`, KindEncoding, `hex("hi there") = 6869207468657265`},
+		{"aGVsbG8= from base64", KindEncoding, `"aGVsbG8=" from base64 = hello`},
+		{"68 69 from hex", KindEncoding, `"68 69" from hex = hi`},
+	}
+	ctx := context.Background()
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			r, err := s.Answer(ctx, search.Request{Query: c.query})
+			require.NoError(t, err)
+			require.NotNil(t, r)
+			require.Equal(t, c.kind, r.Kind)
+			require.Equal(t, c.title, r.Title)
+		})
+	}
+	for _, q := range []string{"", "42", "golang", "weather in Berlin", "12 ft in kg", "10am in Atlantis", "AAAA from base64",
+		"rgb to hex", "jpg to base64", "convert png to base64", "1-800-555-1234", "2019-2020",
+	} {
+		r, err := s.Answer(ctx, search.Request{Query: q})
+		require.NoError(t, err)
+		require.Nil(t, r, q)
+	}
+}
+
+func TestDaylightSaving(t *testing.T) {
+	s := newTestService()
+	s.Now = func() time.Time { return time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC) }
+	r, err := s.Answer(context.Background(), search.Request{Query: "10am PST in Berlin"})
+	require.NoError(t, err)
+	// abbreviations are fixed offsets, while locations follow daylight saving time
+	require.Equal(t, "10:00 PST = 20:00 CEST", r.Title)
+}
+
+func TestSearch(t *testing.T) {
+	s := newTestService()
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "12 ft in m"})
+	defer it.Close()
+	require.True(t, it.Next(ctx))
+	r, ok := it.Result().(*search.AnswerResult)
+	require.True(t, ok)
+	require.Equal(t, "3.6576 m", r.Value)
+	tok := it.Token()
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+
+	it = s.Search(ctx, search.Request{Query: "golang"})
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Err())
+}
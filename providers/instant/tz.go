@@ -0,0 +1,315 @@
+package instant
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "time/tzdata" // embedded tz database, in case the system one is missing
+)
+
+// zoneAbbrs are common time zone abbreviations. They are fixed offsets, not locations,
+// thus "PST" is always UTC-8, even when daylight saving time is in effect.
+var zoneAbbrs = map[string]int{ // offset in minutes
+	"utc":  0,
+	"gmt":  0,
+	"z":    0,
+	"wet":  0,
+	"west": 60,
+	"bst":  60,
+	"cet":  60,
+	"cest": 2 * 60,
+	"eet":  2 * 60,
+	"eest": 3 * 60,
+	"msk":  3 * 60,
+	"ist":  5*60 + 30,
+	"ict":  7 * 60,
+	"sgt":  8 * 60,
+	"hkt":  8 * 60,
+	"kst":  9 * 60,
+	"jst":  9 * 60,
+	"aest": 10 * 60,
+	"aedt": 11 * 60,
+	"nzst": 12 * 60,
+	"nzdt": 13 * 60,
+	"hst":  -10 * 60,
+	"akst": -9 * 60,
+	"akdt": -8 * 60,
+	"pst":  -8 * 60,
+	"pdt":  -7 * 60,
+	"mst":  -7 * 60,
+	"mdt":  -6 * 60,
+	"cst":  -6 * 60,
+	"cdt":  -5 * 60,
+	"est":  -5 * 60,
+	"edt":  -4 * 60,
+}
+
+// zoneCities maps names of large cities and countries with a single time zone to tz database locations.
+var zoneCities = map[string]string{
+	"amsterdam":     "Europe/Amsterdam",
+	"athens":        "Europe/Athens",
+	"auckland":      "Pacific/Auckland",
+	"bangkok":       "Asia/Bangkok",
+	"beijing":       "Asia/Shanghai",
+	"berlin":        "Europe/Berlin",
+	"boston":        "America/New_York",
+	"buenos aires":  "America/Argentina/Buenos_Aires",
+	"cairo":         "Africa/Cairo",
+	"chicago":       "America/Chicago",
+	"china":         "Asia/Shanghai",
+	"delhi":         "Asia/Kolkata",
+	"denver":        "America/Denver",
+	"dubai":         "Asia/Dubai",
+	"dublin":        "Europe/Dublin",
+	"france":        "Europe/Paris",
+	"germany":       "Europe/Berlin",
+	"helsinki":      "Europe/Helsinki",
+	"hong kong":     "Asia/Hong_Kong",
+	"honolulu":      "Pacific/Honolulu",
+	"india":         "Asia/Kolkata",
+	"istanbul":      "Europe/Istanbul",
+	"japan":         "Asia/Tokyo",
+	"jakarta":       "Asia/Jakarta",
+	"johannesburg":  "Africa/Johannesburg",
+	"kiev":          "Europe/Kyiv",
+	"kyiv":          "Europe/Kyiv",
+	"lagos":         "Africa/Lagos",
+	"lisbon":        "Europe/Lisbon",
+	"london":        "Europe/London",
+	"los angeles":   "America/Los_Angeles",
+	"madrid":        "Europe/Madrid",
+	"melbourne":     "Australia/Melbourne",
+	"mexico city":   "America/Mexico_City",
+	"moscow":        "Europe/Moscow",
+	"mumbai":        "Asia/Kolkata",
+	"nairobi":       "Africa/Nairobi",
+	"new york":      "America/New_York",
+	"nyc":           "America/New_York",
+	"paris":         "Europe/Paris",
+	"prague":        "Europe/Prague",
+	"rome":          "Europe/Rome",
+	"san francisco": "America/Los_Angeles",
+	"sao paulo":     "America/Sao_Paulo",
+	"seattle":       "America/Los_Angeles",
+	"seoul":         "Asia/Seoul",
+	"shanghai":      "Asia/Shanghai",
+	"singapore":     "Asia/Singapore",
+	"stockholm":     "Europe/Stockholm",
+	"sydney":        "Australia/Sydney",
+	"tokyo":         "Asia/Tokyo",
+	"toronto":       "America/Toronto",
+	"uk":            "Europe/London",
+	"vancouver":     "America/Vancouver",
+	"vienna":        "Europe/Vienna",
+	"warsaw":        "Europe/Warsaw",
+	"zurich":        "Europe/Zurich",
+}
+
+// lookupZone finds a time zone by an abbreviation ("PST"), a UTC offset ("UTC+5:30"),
+// a city name ("Berlin") or a tz database name ("Europe/Berlin").
+func lookupZone(name string) (*time.Location, bool) {
+	key := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if key == "" {
+		return nil, false
+	}
+	if off, ok := zoneAbbrs[key]; ok {
+		return time.FixedZone(strings.ToUpper(key), off*60), true
+	}
+	if loc, ok := parseOffsetZone(key); ok {
+		return loc, true
+	}
+	if tz, ok := zoneCities[key]; ok {
+		loc, err := time.LoadLocation(tz)
+		return loc, err == nil
+	}
+	if !strings.Contains(key, "/") {
+		return nil, false
+	}
+	// tz database names are case-sensitive, but users rarely type them correctly
+	parts := strings.Split(strings.ReplaceAll(key, " ", "_"), "/")
+	for i, p := range parts {
+		words := strings.Split(p, "_")
+		for j, w := range words {
+			if len(w) <= 2 && i == 0 {
+				w = strings.ToUpper(w) // e.g. "US/Pacific"
+			} else if w != "" {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			words[j] = w
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	for _, tz := range []string{strings.Join(parts, "/"), strings.TrimSpace(name)} {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc, true
+		}
+	}
+	return nil, false
+}
+
+// parseOffsetZone parses zones like "utc+3", "gmt-5" or "utc+5:30".
+func parseOffsetZone(s string) (*time.Location, bool) {
+	var rest string
+	switch {
+	case strings.HasPrefix(s, "utc"):
+		rest = s[3:]
+	case strings.HasPrefix(s, "gmt"):
+		rest = s[3:]
+	default:
+		return nil, false
+	}
+	rest = strings.ReplaceAll(rest, " ", "")
+	if len(rest) < 2 || (rest[0] != '+' && rest[0] != '-') {
+		return nil, false
+	}
+	sign := 1
+	if rest[0] == '-' {
+		sign = -1
+	}
+	hs, ms := rest[1:], ""
+	if i := strings.IndexByte(hs, ':'); i >= 0 {
+		hs, ms = hs[:i], hs[i+1:]
+	}
+	h, err := strconv.Atoi(hs)
+	if err != nil || h > 14 {
+		return nil, false
+	}
+	m := 0
+	if ms != "" {
+		m, err = strconv.Atoi(ms)
+		if err != nil || m >= 60 {
+			return nil, false
+		}
+	}
+	name := "UTC" + rest[:1] + hs
+	if ms != "" {
+		name += ":" + ms
+	}
+	return time.FixedZone(name, sign*(h*3600+m*60)), true
+}
+
+// parseClock parses a time of the day, e.g. "10am", "10:30 pm" or "15:30".
+func parseClock(s string) (hour, min int, ok bool) {
+	s = strings.ReplaceAll(strings.ToLower(s), " ", "")
+	suffix := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		s, suffix = s[:len(s)-2], s[len(s)-2:]
+	} else if !strings.Contains(s, ":") {
+		return 0, 0, false // a plain number is not a time
+	}
+	hs, ms := s, "00"
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		hs, ms = s[:i], s[i+1:]
+	}
+	if len(hs) == 0 || len(hs) > 2 || len(ms) != 2 {
+		return 0, 0, false
+	}
+	h, err := strconv.Atoi(hs)
+	if err != nil || h < 0 {
+		return 0, 0, false
+	}
+	m, err := strconv.Atoi(ms)
+	if err != nil || m < 0 || m >= 60 {
+		return 0, 0, false
+	}
+	switch suffix {
+	case "":
+		if h >= 24 {
+			return 0, 0, false
+		}
+	default:
+		if h < 1 || h > 12 {
+			return 0, 0, false
+		}
+		h %= 12
+		if suffix == "pm" {
+			h += 12
+		}
+	}
+	return h, m, true
+}
+
+// splitClock splits a time with a zone, e.g. "10am PST" or "15:30 Europe/Berlin".
+func splitClock(s string) (hour, min int, loc *time.Location, ok bool) {
+	fields := strings.Fields(s)
+	// the time may take one or two fields: "10am" or "10 am"
+	for n := 1; n <= 2 && n < len(fields); n++ {
+		h, m, ok := parseClock(strings.Join(fields[:n], " "))
+		if !ok {
+			continue
+		}
+		loc, ok := lookupZone(strings.Join(fields[n:], " "))
+		if !ok {
+			continue
+		}
+		return h, m, loc, true
+	}
+	return 0, 0, nil, false
+}
+
+// timePrefixes are prefixes of queries for the current time in a given zone.
+var timePrefixes = []string{
+	"what time is it in ",
+	"what is the time in ",
+	"current time in ",
+	"local time in ",
+	"time now in ",
+	"time in ",
+	"now in ",
+}
+
+// answerTime answers queries like "time in Tokyo" or "10am PST in Berlin".
+func answerTime(q string, now time.Time) *answer {
+	tq := strings.TrimRight(q, "? ")
+	for _, pref := range timePrefixes {
+		if len(tq) <= len(pref) || !strings.EqualFold(tq[:len(pref)], pref) {
+			continue
+		}
+		name := strings.TrimSpace(tq[len(pref):])
+		loc, ok := lookupZone(name)
+		if !ok {
+			return nil
+		}
+		t := now.In(loc)
+		return &answer{
+			Kind:     KindTime,
+			Question: "Time in " + name,
+			Value:    t.Format("15:04 MST, Mon Jan 2"),
+		}
+	}
+	from, to, ok := splitConv(q)
+	if !ok {
+		return nil
+	}
+	h, m, src, ok := splitClock(from)
+	if !ok {
+		return nil
+	}
+	dst, ok := lookupZone(to)
+	if !ok {
+		return nil
+	}
+	y, mon, d := now.In(src).Date()
+	t := time.Date(y, mon, d, h, m, 0, 0, src)
+	res := t.In(dst)
+	val := res.Format("15:04 MST")
+	if days := dayDiff(t, res); days != 0 {
+		val += fmt.Sprintf(" (%+d day)", days)
+	}
+	return &answer{
+		Kind:     KindTime,
+		Question: t.Format("15:04 MST"),
+		Value:    val,
+	}
+}
+
+// dayDiff returns the difference between calendar dates of two times in their own locations.
+func dayDiff(a, b time.Time) int {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	da := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	db := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
@@ -0,0 +1,187 @@
+package instant
+
+import (
+	"strings"
+)
+
+// unit is a unit of measurement. Values are converted to the base unit of the dimension as v*Factor + Offset.
+type unit struct {
+	Symbol string
+	Dim    string
+	Factor float64
+	Offset float64 // only used for temperatures
+}
+
+func (u *unit) toBase(v float64) float64 {
+	return v*u.Factor + u.Offset
+}
+
+func (u *unit) fromBase(v float64) float64 {
+	return (v - u.Offset) / u.Factor
+}
+
+// units maps lower-cased unit names and aliases to units.
+var units = make(map[string]*unit)
+
+func defUnit(dim, sym string, factor float64, aliases ...string) {
+	u := &unit{Symbol: sym, Dim: dim, Factor: factor}
+	units[strings.ToLower(sym)] = u
+	for _, name := range aliases {
+		units[name] = u
+	}
+}
+
+func init() {
+	// length, in meters
+	defUnit("length", "nm", 1e-9, "nanometer", "nanometers", "nanometre", "nanometres")
+	defUnit("length", "µm", 1e-6, "um", "micrometer", "micrometers", "micron", "microns")
+	defUnit("length", "mm", 1e-3, "millimeter", "millimeters", "millimetre", "millimetres")
+	defUnit("length", "cm", 1e-2, "centimeter", "centimeters", "centimetre", "centimetres")
+	defUnit("length", "m", 1, "meter", "meters", "metre", "metres")
+	defUnit("length", "km", 1e3, "kilometer", "kilometers", "kilometre", "kilometres")
+	defUnit("length", "in", 0.0254, "inch", "inches", `"`)
+	defUnit("length", "ft", 0.3048, "foot", "feet", "'")
+	defUnit("length", "yd", 0.9144, "yard", "yards")
+	defUnit("length", "mi", 1609.344, "mile", "miles")
+	defUnit("length", "nmi", 1852, "nautical mile", "nautical miles")
+
+	// mass, in kilograms
+	defUnit("mass", "mg", 1e-6, "milligram", "milligrams")
+	defUnit("mass", "g", 1e-3, "gram", "grams")
+	defUnit("mass", "kg", 1, "kilogram", "kilograms", "kilo", "kilos")
+	defUnit("mass", "t", 1e3, "tonne", "tonnes", "metric ton", "metric tons")
+	defUnit("mass", "oz", 0.028349523125, "ounce", "ounces")
+	defUnit("mass", "lb", 0.45359237, "lbs", "pound", "pounds")
+	defUnit("mass", "st", 6.35029318, "stone", "stones")
+
+	// volume, in liters
+	defUnit("volume", "ml", 1e-3, "milliliter", "milliliters", "millilitre", "millilitres")
+	defUnit("volume", "cl", 1e-2, "centiliter", "centiliters", "centilitre", "centilitres")
+	defUnit("volume", "dl", 1e-1, "deciliter", "deciliters", "decilitre", "decilitres")
+	defUnit("volume", "l", 1, "liter", "liters", "litre", "litres")
+	defUnit("volume", "m³", 1e3, "m3", "cubic meter", "cubic meters", "cubic metre", "cubic metres")
+	defUnit("volume", "gal", 3.785411784, "gallon", "gallons")
+	defUnit("volume", "qt", 0.946352946, "quart", "quarts")
+	defUnit("volume", "pt", 0.473176473, "pint", "pints")
+	defUnit("volume", "cup", 0.2365882365, "cups")
+	defUnit("volume", "fl oz", 0.0295735295625, "floz", "fluid ounce", "fluid ounces")
+	defUnit("volume", "tbsp", 0.01478676478125, "tablespoon", "tablespoons")
+	defUnit("volume", "tsp", 0.00492892159375, "teaspoon", "teaspoons")
+
+	// time, in seconds
+	defUnit("time", "ms", 1e-3, "millisecond", "milliseconds")
+	defUnit("time", "s", 1, "sec", "secs", "second", "seconds")
+	defUnit("time", "min", 60, "mins", "minute", "minutes")
+	defUnit("time", "h", 3600, "hr", "hrs", "hour", "hours")
+	defUnit("time", "d", 86400, "day", "days")
+	defUnit("time", "wk", 7*86400, "week", "weeks")
+	defUnit("time", "yr", 365.25*86400, "year", "years")
+
+	// data, in bytes; prefixes like "KB" are decimal and "KiB" are binary
+	defUnit("data", "bit", 1.0/8, "bits")
+	defUnit("data", "B", 1, "byte", "bytes")
+	defUnit("data", "KB", 1e3, "kilobyte", "kilobytes")
+	defUnit("data", "MB", 1e6, "megabyte", "megabytes")
+	defUnit("data", "GB", 1e9, "gigabyte", "gigabytes")
+	defUnit("data", "TB", 1e12, "terabyte", "terabytes")
+	defUnit("data", "KiB", 1<<10, "kibibyte", "kibibytes")
+	defUnit("data", "MiB", 1<<20, "mebibyte", "mebibytes")
+	defUnit("data", "GiB", 1<<30, "gibibyte", "gibibytes")
+	defUnit("data", "TiB", 1<<40, "tebibyte", "tebibytes")
+
+	// speed, in meters per second
+	defUnit("speed", "m/s", 1, "mps", "meters per second", "metres per second")
+	defUnit("speed", "km/h", 1/3.6, "kmh", "kph", "kilometers per hour", "kilometres per hour")
+	defUnit("speed", "mph", 0.44704, "mi/h", "miles per hour")
+	defUnit("speed", "ft/s", 0.3048, "fps", "feet per second")
+	defUnit("speed", "kn", 1852/3600.0, "kt", "knot", "knots")
+
+	// area, in square meters
+	defUnit("area", "cm²", 1e-4, "cm2", "sq cm", "square centimeter", "square centimeters")
+	defUnit("area", "m²", 1, "m2", "sq m", "square meter", "square meters", "square metre", "square metres")
+	defUnit("area", "km²", 1e6, "km2", "sq km", "square kilometer", "square kilometers")
+	defUnit("area", "ha", 1e4, "hectare", "hectares")
+	defUnit("area", "in²", 0.00064516, "in2", "sq in", "square inch", "square inches")
+	defUnit("area", "ft²", 0.09290304, "ft2", "sq ft", "sqft", "square foot", "square feet")
+	defUnit("area", "mi²", 2589988.110336, "mi2", "sq mi", "square mile", "square miles")
+	defUnit("area", "ac", 4046.8564224, "acre", "acres")
+
+	// temperature, in kelvins
+	defUnit("temperature", "K", 1, "kelvin", "kelvins")
+	units["°c"] = &unit{Symbol: "°C", Dim: "temperature", Factor: 1, Offset: 273.15}
+	units["°f"] = &unit{Symbol: "°F", Dim: "temperature", Factor: 5.0 / 9, Offset: 273.15 - 32*5.0/9}
+	for _, name := range []string{"c", "celsius", "degc", "degrees celsius"} {
+		units[name] = units["°c"]
+	}
+	for _, name := range []string{"f", "fahrenheit", "degf", "degrees fahrenheit"} {
+		units[name] = units["°f"]
+	}
+}
+
+// lookupUnit finds a unit by its name, symbol or alias.
+func lookupUnit(name string) *unit {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	return units[name]
+}
+
+// convSeps are separators between the value and the target of a conversion, e.g. "12 ft in m".
+var convSeps = []string{" in ", " to ", " into ", " as ", "="}
+
+// splitConv splits a conversion query into the source and the target parts.
+// The last separator is used, so "12 in in cm" is split into "12 in" and "cm".
+func splitConv(q string) (from, to string, ok bool) {
+	lq := strings.ToLower(q)
+	best, size := -1, 0
+	for _, sep := range convSeps {
+		if i := strings.LastIndex(lq, sep); i > best {
+			best, size = i, len(sep)
+		}
+	}
+	if best <= 0 {
+		return "", "", false
+	}
+	from, to = strings.TrimSpace(q[:best]), strings.TrimSpace(q[best+size:])
+	if from == "" || to == "" {
+		return "", "", false
+	}
+	return from, to, true
+}
+
+// parseQuantity splits a value with a unit, e.g. "12 ft" or "1.5kg", and evaluates the value.
+func parseQuantity(s string) (float64, *unit, bool) {
+	// try the shortest unit suffix first, so that "12 sq ft" is not parsed as "12 sq" and "ft"
+	for i := len(s) - 1; i > 0; i-- {
+		u := lookupUnit(s[i:])
+		if u == nil {
+			continue
+		}
+		v, _, err := eval(s[:i])
+		if err != nil {
+			continue
+		}
+		return v, u, true
+	}
+	return 0, nil, false
+}
+
+// convertUnits answers queries like "12 ft in m" or "100 °F to °C".
+func convertUnits(q string) *answer {
+	from, to, ok := splitConv(q)
+	if !ok {
+		return nil
+	}
+	dst := lookupUnit(to)
+	if dst == nil {
+		return nil
+	}
+	v, src, ok := parseQuantity(from)
+	if !ok || src.Dim != dst.Dim {
+		return nil
+	}
+	res := dst.fromBase(src.toBase(v))
+	return &answer{
+		Kind:     KindUnit,
+		Question: formatNumber(v) + " " + src.Symbol,
+		Value:    formatNumber(res) + " " + dst.Symbol,
+	}
+}
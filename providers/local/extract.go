@@ -0,0 +1,107 @@
+package local
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// extractFunc returns the title and the plain text of a document.
+type extractFunc func(data []byte) (title, text string, err error)
+
+// extractors for supported file extensions.
+var extractors = map[string]extractFunc{
+	".txt":      extractText,
+	".text":     extractText,
+	".md":       extractMarkdown,
+	".markdown": extractMarkdown,
+	".html":     extractHTML,
+	".htm":      extractHTML,
+}
+
+// Supported reports if the file can be indexed.
+func Supported(path string) bool {
+	_, ok := extractors[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// maxTitle is the maximal length of the title taken from the document text.
+const maxTitle = 120
+
+// firstLine returns the first non-empty line of the text.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			if len(line) > maxTitle {
+				i := strings.LastIndex(line[:maxTitle], " ")
+				if i <= 0 {
+					i = maxTitle
+				}
+				line = strings.ToValidUTF8(line[:i], "") + "…"
+			}
+			return line
+		}
+	}
+	return ""
+}
+
+func extractText(data []byte) (string, string, error) {
+	text := string(bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1))
+	return firstLine(text), text, nil
+}
+
+var (
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}[ \t]+(.+?)[ \t#]*$`)
+	mdSetext  = regexp.MustCompile(`(?m)^(\S.*)\n=+[ \t]*$`)
+	mdImage   = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink    = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdPrefix  = regexp.MustCompile(`(?m)^[ \t]*(?:#{1,6}|>|[-*+]|\d+\.)[ \t]+`)
+	mdRule    = regexp.MustCompile(`(?m)^[ \t]*(?:[-*_=][ \t]*){3,}$`)
+	mdMarkup  = regexp.MustCompile("[*`~]+") // underscores are kept, since they are common in identifiers
+)
+
+func extractMarkdown(data []byte) (string, string, error) {
+	_, text, _ := extractText(data)
+	// the first heading of either style is used as a title
+	title, pos := "", len(text)
+	for _, re := range []*regexp.Regexp{mdHeading, mdSetext} {
+		if m := re.FindStringSubmatchIndex(text); m != nil && m[0] < pos {
+			title, pos = text[m[2]:m[3]], m[0]
+		}
+	}
+	text = mdImage.ReplaceAllString(text, "$1")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = mdPrefix.ReplaceAllString(text, "")
+	text = mdRule.ReplaceAllString(text, "")
+	text = mdMarkup.ReplaceAllString(text, "")
+	title = mdMarkup.ReplaceAllString(mdLink.ReplaceAllString(title, "$1"), "")
+	if title == "" {
+		title = firstLine(text)
+	}
+	return strings.TrimSpace(title), text, nil
+}
+
+// htmlBlocks are elements that separate words in the rendered text.
+const htmlBlocks = "p, div, br, li, tr, td, th, h1, h2, h3, h4, h5, h6, pre, blockquote, section, article"
+
+func extractHTML(data []byte) (string, string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("h1").First().Text())
+	}
+	body := doc.Find("body")
+	body.Find("script, style, noscript, template").Remove()
+	body.Find(htmlBlocks).AfterHtml("\n")
+	text := body.Text()
+	if title == "" {
+		title = firstLine(text)
+	}
+	return strings.Join(strings.Fields(title), " "), text, nil
+}
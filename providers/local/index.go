@@ -0,0 +1,299 @@
+package local
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Document is a single indexed file.
+type Document struct {
+	Path    string
+	Title   string
+	Text    string
+	ModTime time.Time
+
+	terms  map[string]int // term frequencies
+	length int            // number of tokens
+}
+
+// Hit is a document matching the query.
+type Hit struct {
+	Doc   *Document
+	Score float64
+}
+
+// Index is an in-memory inverted index with BM25 ranking. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*Document
+	postings map[string]map[*Document]int // term -> document -> term frequency
+	total    int                          // total number of tokens in all documents
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[*Document]int),
+	}
+}
+
+type word struct {
+	Term       string
+	Start, End int // byte offsets in the text
+}
+
+// tokenize splits the text into lower-cased words.
+func tokenize(text string) []word {
+	var (
+		out   []word
+		start = -1
+	)
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			out = append(out, word{Term: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, word{Term: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+	return out
+}
+
+// terms returns unique terms of the query, in order.
+func terms(query string) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, t := range tokenize(query) {
+		if _, ok := seen[t.Term]; ok {
+			continue
+		}
+		seen[t.Term] = struct{}{}
+		out = append(out, t.Term)
+	}
+	return out
+}
+
+// Len returns the number of indexed documents.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Get returns an indexed document by path.
+func (idx *Index) Get(path string) *Document {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.docs[path]
+}
+
+// Paths returns paths of all indexed documents.
+func (idx *Index) Paths() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	out := make([]string, 0, len(idx.docs))
+	for p := range idx.docs {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Add indexes the document, replacing an existing document with the same path.
+func (idx *Index) Add(d *Document) {
+	d.terms = make(map[string]int)
+	d.length = 0
+	// the title is indexed together with the text, unless the text already starts with it
+	text := d.Text
+	if d.Title != "" && !strings.HasPrefix(text, d.Title) {
+		text = d.Title + "\n" + text
+	}
+	for _, t := range tokenize(text) {
+		d.terms[t.Term]++
+		d.length++
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(d.Path)
+	idx.docs[d.Path] = d
+	idx.total += d.length
+	for term, n := range d.terms {
+		m := idx.postings[term]
+		if m == nil {
+			m = make(map[*Document]int)
+			idx.postings[term] = m
+		}
+		m[d] = n
+	}
+}
+
+// Remove deletes the document from the index.
+func (idx *Index) Remove(path string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(path)
+}
+
+func (idx *Index) remove(path string) {
+	d := idx.docs[path]
+	if d == nil {
+		return
+	}
+	delete(idx.docs, path)
+	idx.total -= d.length
+	for term := range d.terms {
+		m := idx.postings[term]
+		delete(m, d)
+		if len(m) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+}
+
+// Search returns documents that contain any of the query terms, ranked by BM25.
+func (idx *Index) Search(query string) []Hit {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if len(idx.docs) == 0 {
+		return nil
+	}
+	n := float64(len(idx.docs))
+	avg := float64(idx.total) / n
+	scores := make(map[*Document]float64)
+	for _, term := range terms(query) {
+		m := idx.postings[term]
+		if len(m) == 0 {
+			continue
+		}
+		df := float64(len(m))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for d, tf := range m {
+			f := float64(tf)
+			norm := 1 - bm25B + bm25B*float64(d.length)/avg
+			scores[d] += idf * f * (bm25K1 + 1) / (f + bm25K1*norm)
+		}
+	}
+	out := make([]Hit, 0, len(scores))
+	for d, s := range scores {
+		out = append(out, Hit{Doc: d, Score: s})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Doc.Path < out[j].Doc.Path
+	})
+	return out
+}
+
+// Complete returns indexed terms starting with a given prefix, most frequent first.
+func (idx *Index) Complete(prefix string, limit int) []string {
+	prefix = strings.ToLower(prefix)
+	if prefix == "" {
+		return nil
+	}
+	idx.mu.RLock()
+	type termFreq struct {
+		term string
+		df   int
+	}
+	var arr []termFreq
+	for term, m := range idx.postings {
+		if term != prefix && strings.HasPrefix(term, prefix) {
+			arr = append(arr, termFreq{term: term, df: len(m)})
+		}
+	}
+	idx.mu.RUnlock()
+	sort.Slice(arr, func(i, j int) bool {
+		if arr[i].df != arr[j].df {
+			return arr[i].df > arr[j].df
+		}
+		return arr[i].term < arr[j].term
+	})
+	if limit > 0 && len(arr) > limit {
+		arr = arr[:limit]
+	}
+	out := make([]string, 0, len(arr))
+	for _, t := range arr {
+		out = append(out, t.term)
+	}
+	return out
+}
+
+// Snippet returns a fragment of the text of about n bytes, which contains as many query terms as possible.
+// Matched terms are not highlighted.
+func Snippet(text, query string, n int) string {
+	toks := tokenize(text)
+	if len(toks) == 0 {
+		return ""
+	}
+	want := make(map[string]struct{})
+	for _, t := range terms(query) {
+		want[t] = struct{}{}
+	}
+	// find a window of tokens that fits into n bytes and has the most distinct query terms
+	best, bestCnt := 0, 0
+	for i, t := range toks {
+		if _, ok := want[t.Term]; !ok {
+			continue
+		}
+		seen := make(map[string]struct{})
+		for _, t2 := range toks[i:] {
+			if t2.End-t.Start > n {
+				break
+			}
+			if _, ok := want[t2.Term]; ok {
+				seen[t2.Term] = struct{}{}
+			}
+		}
+		if len(seen) > bestCnt {
+			best, bestCnt = i, len(seen)
+		}
+	}
+	// start the snippet a few words before the first match to give it some context
+	start := best
+	for start > 0 && toks[best].Start-toks[start-1].Start < n/4 {
+		start--
+	}
+	from := toks[start].Start
+	to := from
+	for _, t := range toks[start:] {
+		if t.End-from > n {
+			break
+		}
+		to = t.End
+	}
+	// keep punctuation that follows the last word
+	for to < len(text) {
+		r, sz := utf8.DecodeRuneInString(text[to:])
+		if !unicode.IsPunct(r) {
+			break
+		}
+		to += sz
+	}
+	s := strings.Join(strings.Fields(text[from:to]), " ")
+	if strings.TrimSpace(text[:from]) != "" {
+		s = "…" + s
+	}
+	if strings.TrimSpace(text[to:]) != "" {
+		s += "…"
+	}
+	return s
+}
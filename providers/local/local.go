@@ -0,0 +1,335 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "local"
+
+	// EnvDirs is a list of directories with documents to index.
+	EnvDirs = "METAS_LOCAL_DIRS"
+)
+
+var (
+	DefaultPageSize    = 20
+	DefaultSuggestions = 10
+	// SnippetLen is an approximate length of result snippets, in bytes.
+	SnippetLen = 200
+	// RescanInterval is a minimal interval between checks for changed files.
+	RescanInterval = time.Minute
+	// MaxFileSize limits the size of indexed files.
+	MaxFileSize int64 = 8 << 20
+)
+
+func init() {
+	providers.RegisterPrivate(provName, func(ctx context.Context) (providers.Provider, error) {
+		dirs := providers.EnvList(EnvDirs)
+		if len(dirs) == 0 {
+			return nil, nil
+		}
+		return New(dirs...), nil
+	})
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// New creates a provider that indexes text, Markdown and HTML files in given directories.
+// Files are indexed on the first search and are updated when their modification time changes.
+func New(dirs ...string) *Service {
+	return &Service{
+		Dirs: dirs,
+		idx:  NewIndex(),
+	}
+}
+
+type Service struct {
+	Dirs []string
+
+	idx *Index
+
+	mu      sync.Mutex // serializes updates
+	scanned time.Time
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// Index returns the underlying index.
+func (s *Service) Index() *Index {
+	return s.idx
+}
+
+// Update indexes files that were added or changed since the last update, and removes deleted files from the index.
+func (s *Service) Update(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(ctx)
+}
+
+// refresh updates the index, unless it was updated recently.
+func (s *Service) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.scanned.IsZero() && time.Since(s.scanned) < RescanInterval {
+		return nil
+	}
+	return s.update(ctx)
+}
+
+func (s *Service) update(ctx context.Context) error {
+	seen := make(map[string]struct{})
+	for _, dir := range s.Dirs {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir {
+					return err
+				}
+				log.Printf("%s: %v", provName, err)
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") && path != dir {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || !Supported(path) {
+				return nil
+			}
+			fi, err := d.Info()
+			if err != nil {
+				log.Printf("%s: %v", provName, err)
+				return nil
+			} else if fi.Size() > MaxFileSize {
+				return nil
+			}
+			seen[path] = struct{}{}
+			if doc := s.idx.Get(path); doc != nil && doc.ModTime.Equal(fi.ModTime()) {
+				return nil
+			}
+			if err := s.indexFile(path, fi.ModTime()); err != nil {
+				log.Printf("%s: %v", provName, err)
+				delete(seen, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, path := range s.idx.Paths() {
+		if _, ok := seen[path]; !ok {
+			s.idx.Remove(path)
+		}
+	}
+	s.scanned = time.Now()
+	return nil
+}
+
+func (s *Service) indexFile(path string, mtime time.Time) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	title, text, err := extractors[strings.ToLower(filepath.Ext(path))](data)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		title = filepath.Base(path)
+	}
+	s.idx.Add(&Document{Path: path, Title: title, Text: text, ModTime: mtime})
+	return nil
+}
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	// only the last word is completed
+	text := req.Text
+	i := strings.LastIndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) + 1
+	terms := s.idx.Complete(text[i:], DefaultSuggestions)
+	out := make([]string, 0, len(terms))
+	for _, t := range terms {
+		out = append(out, text[:i]+t)
+	}
+	return out, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Total, fetched: true, page: resp.Hits, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []Hit
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Offset+len(it.page) >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.Offset += len(it.page)
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Hits
+	it.total = resp.Total
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i].toResult(it.cur.Query)
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query  string `json:"q"`
+	Offset int    `json:"off,omitempty"`
+}
+
+type SearchResp struct {
+	Hits  []Hit
+	Total int
+}
+
+func (h *Hit) toResult(query string) *search.LinkResult {
+	return &search.LinkResult{
+		URL:   url.URL{Scheme: "file", Path: filepath.ToSlash(h.Doc.Path)},
+		Title: h.Doc.Title,
+		Desc:  Snippet(h.Doc.Text, query, SnippetLen),
+	}
+}
+
+// SearchRaw updates the index if necessary and returns a single page of matching documents.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	hits := s.idx.Search(r.Query)
+	resp := &SearchResp{Total: len(hits)}
+	if r.Offset >= len(hits) {
+		return resp, nil
+	}
+	hits = hits[r.Offset:]
+	if len(hits) > DefaultPageSize {
+		hits = hits[:DefaultPageSize]
+	}
+	resp.Hits = hits
+	return resp, nil
+}
@@ -0,0 +1,179 @@
+package local
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+// copyDir copies test documents to a temporary directory, so they can be modified.
+func copyDir(t testing.TB, src string) string {
+	dst := t.TempDir()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, 0644)
+	})
+	require.NoError(t, err)
+	return dst
+}
+
+func TestExtract(t *testing.T) {
+	cases := []struct {
+		file  string
+		title string
+		text  string
+	}{
+		{file: "notes.txt", title: "Meeting notes", text: "BM25 ranking"},
+		{file: "sub/guide.md", title: "Indexing Guide", text: "The indexer walks directories and reads Markdown files."},
+		{file: "page.html", title: "Gopher Page", text: "Gophers love fast search.\nThey dig tunnels."},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata/docs", c.file))
+			require.NoError(t, err)
+			title, text, err := extractors[filepath.Ext(c.file)](data)
+			require.NoError(t, err)
+			require.Equal(t, c.title, title)
+			require.Contains(t, text, c.text)
+			require.NotContains(t, text, "var search")
+		})
+	}
+	require.False(t, Supported("data.json"))
+}
+
+func TestIndex(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(&Document{Path: "/a", Text: "go search engine written in go"})
+	idx.Add(&Document{Path: "/b", Text: "a search engine"})
+	idx.Add(&Document{Path: "/c", Text: "the gopher is the mascot of go, and it likes to dig for a very long time"})
+	require.Equal(t, 3, idx.Len())
+
+	var paths []string
+	for _, h := range idx.Search("Go engine") {
+		paths = append(paths, h.Doc.Path)
+	}
+	require.Equal(t, []string{"/a", "/b", "/c"}, paths)
+
+	// re-adding a document replaces it
+	idx.Add(&Document{Path: "/a", Text: "nothing to see"})
+	paths = nil
+	for _, h := range idx.Search("go") {
+		paths = append(paths, h.Doc.Path)
+	}
+	require.Equal(t, []string{"/c"}, paths)
+
+	idx.Remove("/c")
+	require.Empty(t, idx.Search("go"))
+	require.Equal(t, 2, idx.Len())
+	require.Equal(t, []string{"search", "see"}, idx.Complete("Se", 0))
+}
+
+func TestSnippet(t *testing.T) {
+	const text = "Lorem ipsum dolor sit amet. The quick brown fox jumps over the lazy dog. " +
+		"Consectetur adipiscing elit, sed do eiusmod tempor. The fox is quick."
+	require.Equal(t, "…brown fox jumps over the lazy dog.…", Snippet(text, "fox lazy-dog", 40))
+	require.Equal(t, "Lorem ipsum dolor…", Snippet(text, "missing", 20))
+	require.Equal(t, "fox is quick.", Snippet("fox is quick.", "quick", 100))
+}
+
+func TestSearch(t *testing.T) {
+	dir := copyDir(t, "testdata/docs")
+	s := New(dir)
+	ctx := context.Background()
+
+	search1 := func(q string) []search.Result {
+		it := s.Search(ctx, search.Request{Query: q})
+		defer it.Close()
+		var out []search.Result
+		for it.Next(ctx) {
+			out = append(out, it.Result())
+		}
+		require.NoError(t, it.Err())
+		return out
+	}
+
+	got := search1("search")
+	require.Len(t, got, 3)
+	require.Equal(t, 3, s.Index().Len())
+	require.Equal(t, &search.LinkResult{
+		URL:   *mustFileURL(filepath.Join(dir, "page.html")),
+		Title: "Gopher Page",
+		Desc:  "Gophers Gophers love fast search. They dig tunnels.",
+	}, got[0])
+	require.Equal(t, "Meeting notes", got[1].GetTitle())
+	require.Equal(t, "Indexing Guide", got[2].GetTitle())
+
+	// modify and remove files; changes are picked up on update
+	path := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Shopping list\n\nmilk, bread"), 0644))
+	mtime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+	require.NoError(t, os.Remove(filepath.Join(dir, "page.html")))
+
+	old := s.Index().Get(filepath.Join(dir, "sub", "guide.md"))
+	require.NoError(t, s.Update(ctx))
+	require.Equal(t, 2, s.Index().Len())
+	require.True(t, old == s.Index().Get(filepath.Join(dir, "sub", "guide.md")), "unchanged files should not be reindexed")
+
+	got = search1("search")
+	require.Len(t, got, 1)
+	got = search1("bread")
+	require.Len(t, got, 1)
+	require.Equal(t, "Shopping list", got[0].GetTitle())
+}
+
+func TestPaging(t *testing.T) {
+	defer func(n int) { DefaultPageSize = n }(DefaultPageSize)
+	DefaultPageSize = 1
+
+	s := New("testdata/docs")
+	ctx := context.Background()
+	it := s.Search(ctx, search.Request{Query: "search"})
+	defer it.Close()
+	require.True(t, it.Next(ctx))
+	require.True(t, it.Next(ctx))
+	second := it.Result().GetTitle()
+	tok := it.Token()
+
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	var rest []string
+	for it.Next(ctx) {
+		rest = append(rest, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, "Meeting notes", second)
+	require.Equal(t, []string{"Indexing Guide"}, rest)
+}
+
+func TestAutoComplete(t *testing.T) {
+	s := New("testdata/docs")
+	got, err := s.AutoComplete(context.Background(), autocomplete.Request{Text: "fast Go"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"fast gopher", "fast gophers"}, got)
+}
+
+func mustFileURL(path string) *url.URL {
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+}
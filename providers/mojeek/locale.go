@@ -0,0 +1,76 @@
+package mojeek
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language/display"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+// languages supported by the Mojeek language bias option.
+var languages = []string{
+	"af", "ar", "hy", "eu", "be", "bg", "ca", "zh", "hr", "cs", "da", "nl",
+	"en", "eo", "et", "fi", "fr", "de", "el", "he", "hu", "is", "id", "it",
+	"ja", "ko", "lv", "lt", "nb", "fa", "pl", "pt", "ro", "ru", "sr", "sk",
+	"sl", "es", "sw", "sv", "th", "tl", "tr", "uk", "vi",
+}
+
+// countries supported by the Mojeek region bias option.
+var countries = []string{
+	"AF", "AL", "DZ", "AR", "AU", "AT", "BE", "BR", "BG", "CA", "CL", "CN",
+	"CO", "HR", "CZ", "DK", "EG", "EE", "FI", "FR", "DE", "GR", "HK", "HU",
+	"IS", "IN", "ID", "IE", "IL", "IT", "JP", "KR", "LV", "LT", "MY", "MX",
+	"NL", "NZ", "NO", "PK", "PE", "PH", "PL", "PT", "RO", "RU", "SA", "RS",
+	"SG", "SK", "SI", "ZA", "ES", "SE", "CH", "TW", "TH", "TR", "UA", "AE",
+	"GB", "US", "VN",
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	names := display.English.Languages()
+	out := make([]search.Language, 0, len(languages))
+	for _, l := range languages {
+		code := search.MustParseLangCode(l)
+		out = append(out, search.Language{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	names := display.English.Regions()
+	out := make([]search.Region, 0, len(countries))
+	for _, c := range countries {
+		code := search.MustParseRegionCode(c)
+		out = append(out, search.Region{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+// toLangBias returns a Mojeek language code for a given language.
+func toLangBias(lang search.LangCode) string {
+	if lang.IsRoot() {
+		return ""
+	}
+	base, _ := lang.Base()
+	for _, v := range languages {
+		if v == base.String() {
+			return v
+		}
+	}
+	return ""
+}
+
+// toRegionBias returns a Mojeek region code for a given region.
+func toRegionBias(reg search.RegionCode) string {
+	if reg == (search.RegionCode{}) {
+		return ""
+	}
+	c := reg.String()
+	for _, v := range countries {
+		if v == c {
+			return strings.ToLower(c)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package mojeek
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+var _ search.Service = (*Service)(nil)
+
+const (
+	provName   = "mojeek"
+	perPage    = 10
+	baseURL    = "https://www.mojeek.com"
+	searchPath = "/search"
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query:      req.Query,
+		Language:   toLangBias(req.Lang),
+		Region:     toRegionBias(req.Region),
+		SafeSearch: req.Safe,
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s   *Service
+	cur SearchReq
+
+	page []Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	it.cur.Offset += len(it.page)
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Results
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return &search.LinkResult{
+		URL: *u, Title: r.Title, Desc: r.Content,
+	}
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query      string `json:"q"`
+	Offset     int    `json:"off"`
+	Language   string `json:"lang"`
+	Region     string `json:"region"`
+	SafeSearch bool   `json:"safe"`
+}
+
+type Result struct {
+	Title   string
+	URL     string
+	Content string
+}
+
+type SearchResp struct {
+	Results []Result
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	if r.Offset > 0 {
+		params.Set("s", strconv.Itoa(r.Offset+1))
+	}
+	if r.SafeSearch {
+		params.Set("safe", "1")
+	} else {
+		params.Set("safe", "0")
+	}
+	if r.Language != "" {
+		params.Set("lb", r.Language)
+		params.Set("lbb", "100")
+	}
+	if r.Region != "" {
+		params.Set("arc", r.Region)
+		params.Set("rbb", "100")
+	}
+	doc, err := s.GetHTML(ctx, searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	out := &SearchResp{}
+	doc.Find(`ul.results-standard > li`).Each(func(_ int, sel *goquery.Selection) {
+		a := sel.Find(`h2 a`).First()
+		link := a.AttrOr("href", "")
+		if !strings.HasPrefix(link, "http") {
+			return
+		}
+		out.Results = append(out.Results, Result{
+			Title:   strings.TrimSpace(a.Text()),
+			URL:     link,
+			Content: strings.TrimSpace(sel.Find(`p.s`).First().Text()),
+		})
+	})
+	return out, nil
+}
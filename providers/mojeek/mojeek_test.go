@@ -0,0 +1,74 @@
+package mojeek
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []*http.Request
+	page := providertest.ServeFile(t, "testdata/solar.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "www.mojeek.com", r.Host)
+		reqs = append(reqs, r)
+		if r.URL.Query().Get("s") == "5" {
+			w.Write([]byte(`<html><body><p>No pages found matching</p></body></html>`))
+			return
+		}
+		page(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{
+		Query:  "solar",
+		Lang:   search.MustParseLangCode("de-AT"),
+		Region: search.MustParseRegionCode("AT"),
+		Safe:   true,
+	})
+	defer it.Close()
+	var (
+		got []search.Result
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+		if len(got) == 3 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 4)
+	require.Equal(t, &search.LinkResult{
+		URL:   *got[0].GetURL(),
+		Title: "Solar energy - Wikipedia",
+		Desc:  "Solar energy is radiant light and heat from the Sun.",
+	}, got[0])
+	require.Equal(t, "https://en.wikipedia.org/wiki/Solar_energy", got[0].GetURL().String())
+
+	require.Len(t, reqs, 3)
+	q := reqs[0].URL.Query()
+	require.Equal(t, "", q.Get("s"))
+	require.Equal(t, "1", q.Get("safe"))
+	require.Equal(t, "de", q.Get("lb"))
+	require.Equal(t, "at", q.Get("arc"))
+	require.Equal(t, "3", reqs[1].URL.Query().Get("s"))
+
+	reqs = nil
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	got = nil
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, 1)
+	require.Equal(t, "https://www.energy.gov/solar", got[0].GetURL().String())
+	require.Len(t, reqs, 2)
+}
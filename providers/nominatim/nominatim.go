@@ -0,0 +1,306 @@
+package nominatim
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "nominatim"
+	baseURL    = "https://nominatim.openstreetmap.org"
+	searchPath = "/search"
+	osmURL     = "https://www.openstreetmap.org/"
+
+	// EnvURL is a base URL of a self-hosted Nominatim instance.
+	EnvURL = "METAS_NOMINATIM_URL"
+
+	// minInterval between requests, as required by the Nominatim usage policy.
+	minInterval = time.Second
+)
+
+var (
+	DefaultLimit = 10
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(os.Getenv(EnvURL)), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+// New creates a Nominatim provider for a given instance. If the URL is empty, the public instance is used.
+func New(base string) *Service {
+	if base == "" {
+		base = baseURL
+	}
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(strings.TrimSuffix(base, "/")),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	mu   sync.Mutex
+	last time.Time // time of the last request
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil // names are translated to any language available in OSM
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// wait blocks until the next request is allowed by the rate limit.
+func (s *Service) wait(ctx context.Context) error {
+	s.mu.Lock()
+	next := s.last.Add(minInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	s.last = next // reserve the slot
+	s.mu.Unlock()
+
+	d := next.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{Query: req.Query}
+	if !req.Lang.IsRoot() {
+		r.Language = req.Lang.String()
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, fetched: true, page: resp, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	fetched bool
+
+	page []Place
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) < DefaultLimit {
+			it.page = nil
+			return false
+		}
+		// Nominatim has no offset parameter; places from previous pages must be excluded instead
+		for _, p := range it.page {
+			it.cur.Exclude = append(it.cur.Exclude, p.PlaceID)
+		}
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query    string  `json:"q"`
+	Language string  `json:"lang,omitempty"`
+	Exclude  []int64 `json:"exclude,omitempty"`
+}
+
+// Place is a single result of the Nominatim search in jsonv2 format.
+type Place struct {
+	PlaceID     int64             `json:"place_id"`
+	OSMType     string            `json:"osm_type"`
+	OSMID       int64             `json:"osm_id"`
+	Lat         string            `json:"lat"`
+	Lon         string            `json:"lon"`
+	Category    string            `json:"category"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	Address     map[string]string `json:"address"`
+	BoundingBox []string          `json:"boundingbox"` // south, north, west, east
+}
+
+func parseFloats(arr ...string) ([]float64, error) {
+	out := make([]float64, 0, len(arr))
+	for _, s := range arr {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (p *Place) toResult() (*search.PlaceResult, error) {
+	u, err := url.Parse(osmURL + p.OSMType + "/" + strconv.FormatInt(p.OSMID, 10))
+	if err != nil {
+		return nil, err
+	}
+	title := p.Name
+	if title == "" {
+		title = strings.TrimSpace(strings.SplitN(p.DisplayName, ",", 2)[0])
+	}
+	r := &search.PlaceResult{
+		LinkResult: search.LinkResult{URL: *u, Title: title, Desc: p.DisplayName},
+		Address:    p.Address,
+		Category:   p.Category,
+		Type:       p.Type,
+		OSMType:    p.OSMType,
+		OSMID:      p.OSMID,
+	}
+	ll, err := parseFloats(p.Lat, p.Lon)
+	if err != nil {
+		return nil, err
+	}
+	r.Lat, r.Lon = ll[0], ll[1]
+	if len(p.BoundingBox) == 4 {
+		b, err := parseFloats(p.BoundingBox...)
+		if err != nil {
+			return nil, err
+		}
+		r.Bounds = &search.BoundingBox{South: b[0], North: b[1], West: b[2], East: b[3]}
+	}
+	return r, nil
+}
+
+// SearchRaw runs a single search request. Requests are limited to one per second.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) ([]Place, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("limit", strconv.Itoa(DefaultLimit))
+	if r.Language != "" {
+		params.Set("accept-language", r.Language)
+	}
+	if len(r.Exclude) != 0 {
+		ids := make([]string, 0, len(r.Exclude))
+		for _, id := range r.Exclude {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		params.Set("exclude_place_ids", strings.Join(ids, ","))
+	}
+	req, err := s.GetRequest(searchPath, params)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", providers.UserAgent)
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	var out []Place
+	if _, err := s.DoJSON(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package nominatim
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	defer func(n int) { DefaultLimit = n }(DefaultLimit)
+	DefaultLimit = 2
+
+	var (
+		reqs  []url.Values
+		times []time.Time
+	)
+	page0 := providertest.ServeFile(t, "testdata/search_0.json", "application/json")
+	page1 := providertest.ServeFile(t, "testdata/search_1.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "osm.example.com", r.Host)
+		require.Equal(t, "/nominatim"+searchPath, r.URL.Path)
+		require.Equal(t, providers.UserAgent, r.Header.Get("User-Agent"))
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		times = append(times, time.Now())
+		if q.Get("exclude_place_ids") == "" {
+			page0(w, r)
+		} else {
+			page1(w, r)
+		}
+	}))
+	s := New("https://osm.example.com/nominatim/")
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "Berlin", Lang: search.MustParseLangCode("de")})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, "de", reqs[0].Get("accept-language"))
+	require.Equal(t, "jsonv2", reqs[0].Get("format"))
+	require.Equal(t, "240109189,298436510", reqs[1].Get("exclude_place_ids"))
+	require.True(t, times[1].Sub(times[0]) >= minInterval-10*time.Millisecond, "requests must be rate limited")
+
+	require.Len(t, got, 3)
+	require.Equal(t, &search.PlaceResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://www.openstreetmap.org/relation/62422"),
+			Title: "Berlin",
+			Desc:  "Berlin, Deutschland",
+		},
+		Lat:    52.5170365,
+		Lon:    13.3888599,
+		Bounds: &search.BoundingBox{South: 52.3382448, North: 52.6755087, West: 13.0883450, East: 13.7611609},
+		Address: map[string]string{
+			"city": "Berlin", "ISO3166-2-lvl4": "DE-BE", "country": "Deutschland", "country_code": "de",
+		},
+		Category: "boundary",
+		Type:     "administrative",
+		OSMType:  "relation",
+		OSMID:    62422,
+	}, got[0])
+	require.Equal(t, "Berlin Hauptbahnhof", got[1].GetTitle())
+	require.Equal(t, -71.1851, got[2].(*search.PlaceResult).Lon)
+}
+
+func TestRateLimitCancel(t *testing.T) {
+	s := New("")
+	ctx := context.Background()
+	require.NoError(t, s.wait(ctx))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, s.wait(ctx))
+}
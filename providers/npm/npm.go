@@ -0,0 +1,248 @@
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "npm"
+	baseURL    = "https://registry.npmjs.org"
+	searchPath = "/-/v1/search"
+	pkgURL     = "https://www.npmjs.com/package/"
+)
+
+var (
+	DefaultSize = 20
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, Size: DefaultSize}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Total, fetched: true, page: resp.Objects, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []Object
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.From+len(it.page) >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.From += len(it.page)
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Objects
+	it.total = resp.Total
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	From  int    `json:"from"`
+	Size  int    `json:"size"`
+}
+
+type Package struct {
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
+	License     string    `json:"license"`
+	Links       struct {
+		NPM        string `json:"npm"`
+		Homepage   string `json:"homepage"`
+		Repository string `json:"repository"`
+	} `json:"links"`
+}
+
+// Object is a single search result.
+type Object struct {
+	Package   Package `json:"package"`
+	Downloads struct {
+		Monthly int `json:"monthly"`
+		Weekly  int `json:"weekly"`
+	} `json:"downloads"`
+}
+
+// repoURL normalizes repository URLs such as "git+https://github.com/user/repo.git".
+func repoURL(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, nil
+	}
+	s = strings.TrimPrefix(s, "git+")
+	s = strings.TrimSuffix(s, ".git")
+	return url.Parse(s)
+}
+
+func (o *Object) toResult() (*search.PackageResult, error) {
+	p := &o.Package
+	addr := p.Links.NPM
+	if addr == "" {
+		addr = pkgURL + p.Name
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repoURL(p.Links.Repository)
+	if err != nil {
+		return nil, err
+	}
+	return &search.PackageResult{
+		LinkResult: search.LinkResult{URL: *u, Title: p.Name, Desc: p.Description},
+		Name:       p.Name,
+		Version:    p.Version,
+		License:    p.License,
+		Downloads:  o.Downloads.Monthly,
+		Repository: repo,
+		Published:  p.Date,
+	}, nil
+}
+
+type SearchResp struct {
+	Objects []Object `json:"objects"`
+	Total   int      `json:"total"`
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("text", r.Query)
+	if r.Size > 0 {
+		params.Set("size", strconv.Itoa(r.Size))
+	}
+	if r.From > 0 {
+		params.Set("from", strconv.Itoa(r.From))
+	}
+	var out SearchResp
+	if err := s.GetJSON(ctx, searchPath, params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,63 @@
+package npm
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page0 := providertest.ServeFile(t, "testdata/search_0.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/search_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "registry.npmjs.org", r.Host)
+		require.Equal(t, searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("from") == "" {
+			page0(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "express"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []url.Values{
+		{"text": {"express"}, "size": {"20"}},
+		{"text": {"express"}, "size": {"20"}, "from": {"2"}},
+	}, reqs)
+	require.Len(t, got, 3)
+	require.Equal(t, &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://www.npmjs.com/package/express"),
+			Title: "express",
+			Desc:  "Fast, unopinionated, minimalist web framework",
+		},
+		Name:       "express",
+		Version:    "4.19.2",
+		License:    "MIT",
+		Downloads:  118443520,
+		Repository: providertest.MustURL("https://github.com/expressjs/express"),
+		Published:  time.Date(2024, 3, 25, 19, 24, 24, 146000000, time.UTC),
+	}, got[0])
+	lite := got[1].(*search.PackageResult)
+	require.Equal(t, "https://www.npmjs.com/package/express-lite", lite.GetURL().String())
+	require.Nil(t, lite.Repository)
+	require.Equal(t, "@types/express", got[2].GetTitle())
+}
@@ -0,0 +1,156 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dennwc/metasearch/providers"
+)
+
+const (
+	TypeRSS         = "application/rss+xml"
+	TypeAtom        = "application/atom+xml"
+	TypeSuggestions = "application/x-suggestions+json"
+)
+
+// Description is an OpenSearch description document.
+type Description struct {
+	XMLName     xml.Name `xml:"OpenSearchDescription"`
+	ShortName   string   `xml:"ShortName"`
+	Description string   `xml:"Description"`
+	URLs        []URL    `xml:"Url"`
+	Languages   []string `xml:"Language"`
+}
+
+// URL is a template of the search URL.
+type URL struct {
+	Type        string `xml:"type,attr"`
+	Template    string `xml:"template,attr"`
+	Rel         string `xml:"rel,attr"`
+	IndexOffset *int   `xml:"indexOffset,attr"`
+	PageOffset  *int   `xml:"pageOffset,attr"`
+}
+
+// Find returns the first results template with a given type.
+func (d *Description) Find(typ string) *URL {
+	for i, u := range d.URLs {
+		if u.Type == typ && (u.Rel == "" || u.Rel == "results") {
+			return &d.URLs[i]
+		}
+	}
+	return nil
+}
+
+// ReadDescription loads OpenSearch description from a URL or a local file.
+func ReadDescription(ctx context.Context, cli *providers.HTTPClient, src string) (*Description, error) {
+	var d Description
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		if err := cli.GetXML(ctx, src, nil, &d); err != nil {
+			return nil, err
+		}
+	} else {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := xml.NewDecoder(f).Decode(&d); err != nil {
+			return nil, fmt.Errorf("cannot parse %q: %v", src, err)
+		}
+	}
+	if d.ShortName == "" {
+		return nil, fmt.Errorf("opensearch description %q has no name", src)
+	}
+	return &d, nil
+}
+
+var reParam = regexp.MustCompile(`\{([^}?]+)(\?)?\}`)
+
+// Has checks if the template contains a given parameter.
+func (u *URL) Has(name string) bool {
+	for _, m := range reParam.FindAllStringSubmatch(u.Template, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *URL) indexOffset() int {
+	if u.IndexOffset != nil {
+		return *u.IndexOffset
+	}
+	return 1
+}
+
+func (u *URL) pageOffset() int {
+	if u.PageOffset != nil {
+		return *u.PageOffset
+	}
+	return 1
+}
+
+// Params are the values for template parameters.
+type Params struct {
+	Query    string
+	Language string
+	Count    int
+	Offset   int // zero-based index of the first result
+}
+
+// Expand substitutes template parameters and returns the resulting URL.
+// Unknown optional parameters are omitted; unknown required parameters cause an error.
+func (u *URL) Expand(p Params) (string, error) {
+	var err error
+	out := reParam.ReplaceAllStringFunc(u.Template, func(s string) string {
+		m := reParam.FindStringSubmatch(s)
+		name, optional := m[1], m[2] != ""
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[i+1:] // namespaced parameters are not supported
+			if !optional && err == nil {
+				err = fmt.Errorf("unsupported template parameter: %q", m[1])
+			}
+			return ""
+		}
+		switch name {
+		case "searchTerms":
+			return url.QueryEscape(p.Query)
+		case "count":
+			if p.Count > 0 {
+				return strconv.Itoa(p.Count)
+			}
+		case "startIndex":
+			return strconv.Itoa(u.indexOffset() + p.Offset)
+		case "startPage":
+			page := 0
+			if p.Count > 0 {
+				page = p.Offset / p.Count
+			}
+			return strconv.Itoa(u.pageOffset() + page)
+		case "language":
+			if p.Language != "" {
+				return url.QueryEscape(p.Language)
+			}
+			if !optional {
+				return "*"
+			}
+		case "inputEncoding", "outputEncoding":
+			return "UTF-8"
+		default:
+			if !optional && err == nil {
+				err = fmt.Errorf("unsupported template parameter: %q", name)
+			}
+		}
+		return ""
+	})
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
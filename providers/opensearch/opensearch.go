@@ -0,0 +1,408 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "opensearch"
+
+	// EnvDescriptions is a list of OpenSearch description URLs or files to create providers from.
+	EnvDescriptions = "METAS_OPENSEARCH"
+)
+
+var (
+	DefaultCount = 10
+)
+
+func init() {
+	for _, src := range providers.EnvList(EnvDescriptions) {
+		src := src
+		providers.Register(provName+":"+src, func(ctx context.Context) (providers.Provider, error) {
+			return loadProvider(ctx, src)
+		})
+	}
+}
+
+// loadProvider is like Load, but it logs the error and returns no provider instead,
+// so a single broken description does not fail all other providers.
+func loadProvider(ctx context.Context, src string) (providers.Provider, error) {
+	s, err := Load(ctx, src)
+	if err != nil {
+		log.Printf("opensearch: %v", err)
+		return nil, nil
+	}
+	return s, nil
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+var (
+	idMu  sync.Mutex
+	idSrc = make(map[string]string) // provider ID -> description source
+)
+
+// uniqueID returns a provider ID for a description loaded from src.
+// Descriptions with the same short name get a numeric suffix.
+func uniqueID(id, src string) string {
+	idMu.Lock()
+	defer idMu.Unlock()
+	base := id
+	for n := 2; ; n++ {
+		if s, ok := idSrc[id]; !ok || s == src {
+			idSrc[id] = src
+			return id
+		}
+		id = base + "-" + strconv.Itoa(n)
+	}
+}
+
+// Load reads an OpenSearch description from a URL or a file and creates a provider for it.
+// Providers loaded from different sources always have different IDs.
+func Load(ctx context.Context, src string) (*Service, error) {
+	cli := providers.NewHTTPClient("")
+	d, err := ReadDescription(ctx, &cli, src)
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", src, err)
+	}
+	s.HTTPClient = cli
+	s.id = uniqueID(s.id, src)
+	return s, nil
+}
+
+// New creates a provider from an OpenSearch description.
+func New(d *Description) (*Service, error) {
+	s := &Service{
+		HTTPClient: providers.NewHTTPClient(""),
+		desc:       d,
+		id:         provName + "-" + slug(d.ShortName),
+	}
+	if s.results = d.Find(TypeRSS); s.results == nil {
+		s.results = d.Find(TypeAtom)
+	}
+	if s.results == nil {
+		return nil, fmt.Errorf("no RSS or Atom results template in %q", d.ShortName)
+	}
+	s.suggest = d.Find(TypeSuggestions)
+	return s, nil
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	desc    *Description
+	id      string
+	results *URL
+	suggest *URL // optional
+}
+
+// slug converts a name to a lower-case identifier.
+func slug(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, s)
+	for strings.Contains(s, "--") {
+		s = strings.Replace(s, "--", "-", -1)
+	}
+	return strings.Trim(s, "-")
+}
+
+func (s *Service) ID() string {
+	return s.id
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	var out []search.Language
+	for _, l := range s.desc.Languages {
+		if l == "*" {
+			return nil, nil
+		}
+		code, err := search.ParseLangCode(l)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse language %q: %v", l, err)
+		}
+		out = append(out, search.Language{Code: code, Name: l})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{Query: req.Query}
+	if !req.Lang.IsRoot() {
+		r.Language = req.Lang.String()
+	}
+	if s.results.Has("count") {
+		r.Count = DefaultCount
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, page: resp.Results, i: t.Off}
+}
+
+// paged checks if the results template supports pagination.
+func (s *Service) paged() bool {
+	return s.results.Has("startIndex") || s.results.Has("startPage")
+}
+
+type searchIter struct {
+	s   *Service
+	cur SearchReq
+
+	page []Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.page != nil {
+		if !it.s.paged() || len(it.page) == 0 {
+			it.page = nil
+			return false
+		}
+		if it.cur.Count == 0 {
+			// page size is defined by the server; assume it's constant
+			it.cur.Count = len(it.page)
+		}
+		it.cur.Offset += len(it.page)
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = resp.Results
+	if it.page == nil {
+		it.page = []Result{}
+	}
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return &search.LinkResult{
+		URL: *u, Title: r.Title, Desc: r.Content,
+	}
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query    string `json:"q"`
+	Language string `json:"lang"`
+	Offset   int    `json:"off"`
+	Count    int    `json:"count"`
+}
+
+type Result struct {
+	Title   string
+	URL     string
+	Content string
+}
+
+type SearchResp struct {
+	Total   int
+	Results []Result
+}
+
+// feed is either an RSS or an Atom feed.
+type feed struct {
+	Total   int `xml:"totalResults"`
+	Channel struct {
+		Total int `xml:"totalResults"`
+		Items []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			Desc  string `xml:"description"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary string `xml:"summary"`
+		Content string `xml:"content"`
+	} `xml:"entry"`
+}
+
+// plainText strips HTML tags from feed content.
+func plainText(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "<") {
+		return s
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+func (f *feed) results() *SearchResp {
+	out := &SearchResp{Total: f.Total}
+	if f.Channel.Total != 0 {
+		out.Total = f.Channel.Total
+	}
+	for _, it := range f.Channel.Items {
+		out.Results = append(out.Results, Result{
+			Title: plainText(it.Title), URL: strings.TrimSpace(it.Link), Content: plainText(it.Desc),
+		})
+	}
+	for _, e := range f.Entries {
+		r := Result{Title: plainText(e.Title), Content: plainText(e.Summary)}
+		if r.Content == "" {
+			r.Content = plainText(e.Content)
+		}
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				r.URL = l.Href
+				break
+			}
+		}
+		out.Results = append(out.Results, r)
+	}
+	return out
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	addr, err := s.results.Expand(Params{
+		Query: r.Query, Language: r.Language,
+		Count: r.Count, Offset: r.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var f feed
+	if err := s.GetXML(ctx, addr, nil, &f); err != nil {
+		if _, ok := err.(*xml.SyntaxError); ok {
+			return nil, fmt.Errorf("%s: cannot parse results: %v", s.id, err)
+		}
+		return nil, err
+	}
+	return f.results(), nil
+}
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	if s.suggest == nil {
+		return nil, nil
+	}
+	p := Params{Query: req.Text}
+	if !req.Lang.IsRoot() {
+		p.Language = req.Lang.String()
+	}
+	addr, err := s.suggest.Expand(p)
+	if err != nil {
+		return nil, err
+	}
+	// OpenSearch suggestions format: ["query", ["suggestion", ...], ...]
+	var resp []json.RawMessage
+	if err := s.GetJSON(ctx, addr, nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("unexpected suggestions response")
+	}
+	var list []string
+	if err := json.Unmarshal(resp[1], &list); err != nil {
+		return nil, fmt.Errorf("cannot parse suggestions: %v", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,182 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	zero := 0
+	u := URL{
+		Template:    "https://example.com/?q={searchTerms}&s={startIndex}&p={startPage?}&n={count?}&l={language?}&x={foo:bar?}&e={inputEncoding}",
+		IndexOffset: &zero,
+	}
+	got, err := u.Expand(Params{Query: "a b&c", Language: "de", Count: 20, Offset: 40})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/?q=a+b%26c&s=40&p=3&n=20&l=de&x=&e=UTF-8", got)
+
+	u = URL{Template: "https://example.com/?q={searchTerms}&x={unknown}"}
+	_, err = u.Expand(Params{Query: "a"})
+	require.Error(t, err)
+}
+
+func TestSearchRSS(t *testing.T) {
+	var reqs []url.Values
+	rss := providertest.ServeFile(t, "testdata/results.rss", "application/rss+xml")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "wiki.example.com", r.Host)
+		switch r.URL.Path {
+		case "/search/rss":
+			q := r.URL.Query()
+			reqs = append(reqs, q)
+			if q.Get("start") != "0" {
+				w.Write([]byte(`<rss version="2.0"><channel></channel></rss>`))
+				return
+			}
+			rss(w, r)
+		case "/suggest":
+			w.Write([]byte(`["dep",["deploy","deploy guide","dependencies"]]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	ctx := context.Background()
+	s, err := Load(ctx, "testdata/wiki.xml")
+	require.NoError(t, err)
+	s.SetHTTPClient(cli)
+	require.Equal(t, "opensearch-team-wiki", s.ID())
+
+	langs, err := s.Languages(ctx)
+	require.NoError(t, err)
+	require.Len(t, langs, 2)
+
+	it := s.Search(ctx, search.Request{Query: "deploy", Lang: search.MustParseLangCode("de")})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []search.Result{
+		&search.LinkResult{
+			URL:   url.URL{Scheme: "https", Host: "wiki.example.com", Path: "/Deploy_guide"},
+			Title: "Deploy guide",
+			Desc:  "How to deploy the service.",
+		},
+		&search.LinkResult{
+			URL:   url.URL{Scheme: "https", Host: "wiki.example.com", Path: "/Rollback"},
+			Title: "Rollback",
+			Desc:  "Reverting a bad deploy.",
+		},
+	}, got)
+	require.Len(t, reqs, 2)
+	require.Equal(t, "deploy", reqs[0].Get("q"))
+	require.Equal(t, "10", reqs[0].Get("n"))
+	require.Equal(t, "de", reqs[0].Get("hl"))
+	require.Equal(t, "2", reqs[1].Get("start"))
+
+	list, err := s.AutoComplete(ctx, autocomplete.Request{Text: "dep"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"deploy", "deploy guide", "dependencies"}, list)
+}
+
+func TestSearchAtom(t *testing.T) {
+	var pages []string
+	atom := providertest.ServeFile(t, "testdata/results.atom", "application/atom+xml")
+	desc := providertest.ServeFile(t, "testdata/tracker.xml", "application/opensearchdescription+xml")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "tracker.example.com", r.Host)
+		switch r.URL.Path {
+		case "/opensearch.xml":
+			desc(w, r)
+		case "/issues.atom":
+			page := r.URL.Query().Get("page")
+			pages = append(pages, page)
+			if page == "3" {
+				w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+				return
+			}
+			atom(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	ctx := context.Background()
+
+	hc := providers.NewHTTPClient("")
+	hc.SetHTTPClient(cli)
+	d, err := ReadDescription(ctx, &hc, "https://tracker.example.com/opensearch.xml")
+	require.NoError(t, err)
+	s, err := New(d)
+	require.NoError(t, err)
+	s.SetHTTPClient(cli)
+	require.Equal(t, "opensearch-issue-tracker", s.ID())
+
+	langs, err := s.Languages(ctx)
+	require.NoError(t, err)
+	require.Nil(t, langs)
+
+	it := s.Search(ctx, search.Request{Query: "crash"})
+	defer it.Close()
+	var (
+		got []string
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		r := it.Result()
+		got = append(got, r.GetURL().String()+" "+r.GetDesc())
+		if len(got) == 3 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{
+		"https://tracker.example.com/issues/1 The service crashes when the config is empty.",
+		"https://tracker.example.com/issues/2 Panic in the close handler.",
+		"https://tracker.example.com/issues/1 The service crashes when the config is empty.",
+		"https://tracker.example.com/issues/2 Panic in the close handler.",
+	}, got)
+	require.Equal(t, []string{"1", "2", "3"}, pages)
+
+	pages = nil
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	got = nil
+	for it.Next(ctx) {
+		got = append(got, it.Result().GetURL().String())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"https://tracker.example.com/issues/2"}, got)
+	require.Equal(t, []string{"2", "3"}, pages)
+
+	list, err := s.AutoComplete(ctx, autocomplete.Request{Text: "cr"})
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestLoadProvider(t *testing.T) {
+	ctx := context.Background()
+	p, err := loadProvider(ctx, "testdata/missing.xml")
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	// descriptions with the same name get different IDs
+	s1, err := Load(ctx, "testdata/tracker.xml")
+	require.NoError(t, err)
+	s2, err := Load(ctx, "./testdata/tracker.xml")
+	require.NoError(t, err)
+	require.Equal(t, "opensearch-issue-tracker", s1.ID())
+	require.Equal(t, "opensearch-issue-tracker-2", s2.ID())
+
+	s1, err = Load(ctx, "testdata/tracker.xml")
+	require.NoError(t, err)
+	require.Equal(t, "opensearch-issue-tracker", s1.ID())
+}
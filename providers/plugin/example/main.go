@@ -0,0 +1,167 @@
+// Command example is a reference metasearch plugin. It searches a small built-in list of Go packages.
+//
+// It is used in tests, thus it also simulates failures: the "crash" query makes the plugin exit
+// and the "hang" query makes it stop responding.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const pageSize = 2
+
+type request struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type response struct {
+	Version string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *rpcError   `json:"error,omitempty"`
+}
+
+type result struct {
+	Type    string    `json:"type"`
+	URL     string    `json:"url"`
+	Title   string    `json:"title"`
+	Desc    string    `json:"desc"`
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	License string    `json:"license"`
+	Date    time.Time `json:"date"`
+}
+
+type page struct {
+	Results []result `json:"results"`
+	Next    *token   `json:"next"`
+}
+
+// token is an opaque value for metasearch, the plugin decides what to store in it.
+type token struct {
+	Query  string `json:"q"`
+	Offset int    `json:"off"`
+}
+
+var packages = []struct {
+	name, desc string
+}{
+	{"net/http", "Package http provides HTTP client and server implementations."},
+	{"net/http/httptest", "Package httptest provides utilities for HTTP testing."},
+	{"net/url", "Package url parses URLs and implements query escaping."},
+	{"encoding/json", "Package json implements encoding and decoding of JSON."},
+	{"encoding/xml", "Package xml implements a simple XML 1.0 parser."},
+	{"os/exec", "Package exec runs external commands."},
+}
+
+var released = time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC)
+
+func search(t token) page {
+	var out page
+	n := 0
+	for _, p := range packages {
+		if !strings.Contains(p.name, t.Query) && !strings.Contains(strings.ToLower(p.desc), t.Query) {
+			continue
+		}
+		n++
+		if n <= t.Offset {
+			continue
+		}
+		if len(out.Results) == pageSize {
+			out.Next = &token{Query: t.Query, Offset: t.Offset + pageSize}
+			break
+		}
+		out.Results = append(out.Results, result{
+			Type:    "package",
+			URL:     "https://pkg.go.dev/" + p.name,
+			Title:   p.name,
+			Desc:    p.desc,
+			Name:    p.name,
+			Version: "go1.22.0",
+			License: "BSD-3-Clause",
+			Date:    released,
+		})
+	}
+	return out
+}
+
+func complete(prefix string) []string {
+	out := []string{}
+	for _, p := range packages {
+		if strings.HasPrefix(p.name, prefix) {
+			out = append(out, p.name)
+		}
+	}
+	return out
+}
+
+func handle(req *request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "id":
+		return "example", nil
+	case "languages":
+		return []map[string]string{{"code": "en", "name": "English"}}, nil
+	case "search":
+		var p struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: -32602, Message: err.Error()}
+		}
+		switch p.Query {
+		case "crash":
+			fmt.Fprintln(os.Stderr, "example: crashing as requested")
+			os.Exit(2)
+		case "hang":
+			time.Sleep(time.Hour)
+		}
+		return search(token{Query: strings.ToLower(p.Query)}), nil
+	case "continue":
+		var p struct {
+			Token token `json:"token"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: -32602, Message: err.Error()}
+		}
+		return search(p.Token), nil
+	case "autocomplete":
+		var p struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: -32602, Message: err.Error()}
+		}
+		return complete(p.Text), nil
+	}
+	return nil, &rpcError{Code: -32601, Message: "method not found: " + req.Method}
+}
+
+func main() {
+	sc := bufio.NewScanner(os.Stdin)
+	sc.Buffer(nil, 1<<20)
+	enc := json.NewEncoder(os.Stdout)
+	for sc.Scan() {
+		var req request
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, "example:", err)
+			continue
+		}
+		res, err := handle(&req)
+		if err := enc.Encode(response{Version: "2.0", ID: req.ID, Result: res, Error: err}); err != nil {
+			fmt.Fprintln(os.Stderr, "example:", err)
+			os.Exit(1)
+		}
+	}
+}
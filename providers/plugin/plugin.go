@@ -0,0 +1,363 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "plugin"
+
+	// EnvPlugins is a list of plugin executables.
+	EnvPlugins = "METAS_PLUGINS"
+)
+
+var (
+	// DefaultTimeout limits the duration of a single call. A plugin that does not respond in time is restarted.
+	DefaultTimeout = 30 * time.Second
+	// RestartDelay is a minimal interval between restarts of a plugin that keeps crashing.
+	RestartDelay = time.Second
+)
+
+func init() {
+	// each plugin is a separate provider with its own ID
+	for _, path := range providers.EnvList(EnvPlugins) {
+		path := path
+		providers.RegisterPrivate(provName+":"+path, func(ctx context.Context) (providers.Provider, error) {
+			return New(ctx, path)
+		})
+	}
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// New starts a plugin executable and requests its provider ID.
+// The plugin is restarted if it crashes or does not respond in time.
+func New(ctx context.Context, path string, args ...string) (*Service, error) {
+	s := &Service{Path: path, Args: args, Timeout: DefaultTimeout}
+	var id string
+	if err := s.call(ctx, MethodID, nil, &id); err != nil {
+		s.Close()
+		return nil, err
+	} else if id == "" {
+		s.Close()
+		return nil, fmt.Errorf("plugin %s: empty provider id", path)
+	}
+	s.id = id
+	return s, nil
+}
+
+type Service struct {
+	Path    string
+	Args    []string
+	Timeout time.Duration
+
+	id string
+
+	mu      sync.Mutex
+	proc    *process
+	started time.Time
+	lastID  int64
+}
+
+func (s *Service) ID() string {
+	return s.id
+}
+
+// Close stops the plugin. It will be started again on the next call.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.proc != nil {
+		s.proc.kill()
+		<-s.proc.done
+		s.proc = nil
+	}
+	return nil
+}
+
+// process returns a running plugin process, starting it if necessary.
+func (s *Service) process() (*process, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.proc != nil && !s.proc.exited() {
+		return s.proc, nil
+	}
+	if s.proc != nil {
+		log.Println(s.proc.exitErr())
+		s.proc = nil
+	}
+	if !s.started.IsZero() && time.Since(s.started) < RestartDelay {
+		return nil, fmt.Errorf("plugin %s: crashed, will restart in %v", s.Path, RestartDelay-time.Since(s.started))
+	}
+	p, err := startProcess(s.Path, s.Args)
+	if err != nil {
+		return nil, err
+	}
+	s.proc, s.started = p, time.Now()
+	return p, nil
+}
+
+// call calls a plugin method and decodes the result into dst.
+func (s *Service) call(ctx context.Context, method string, params, dst interface{}) error {
+	p, err := s.process()
+	if err != nil {
+		return err
+	}
+	req := &Request{Version: "2.0", Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+	s.mu.Lock()
+	s.lastID++
+	req.ID = s.lastID
+	s.mu.Unlock()
+
+	cctx := ctx
+	if s.Timeout > 0 {
+		var cancel func()
+		cctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	resp, err := p.call(cctx, req)
+	if err == context.DeadlineExceeded && ctx.Err() == nil {
+		// the plugin may be stuck, restart it on the next call
+		p.kill()
+		<-p.done
+		return fmt.Errorf("plugin %s: %s timed out after %v", s.Path, method, s.Timeout)
+	} else if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if dst == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, dst)
+}
+
+// isNotFound checks if the plugin does not support the method.
+func isNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.Code == CodeMethodNotFound
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	var list []Locale
+	if err := s.call(ctx, MethodLanguages, nil, &list); isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	out := make([]search.Language, 0, len(list))
+	for _, l := range list {
+		code, err := search.ParseLangCode(l.Code)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, search.Language{Code: code, Name: l.Name})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	var list []Locale
+	if err := s.call(ctx, MethodRegions, nil, &list); isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	out := make([]search.Region, 0, len(list))
+	for _, l := range list {
+		code, err := search.ParseRegionCode(l.Code)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, search.Region{Code: code, Name: l.Name})
+	}
+	return out, nil
+}
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	params := CompleteParams{Text: req.Text}
+	if !req.Lang.IsRoot() {
+		params.Lang = req.Lang.String()
+	}
+	if req.Region != (search.RegionCode{}) {
+		params.Region = req.Region.String()
+	}
+	var out []string
+	if err := s.call(ctx, MethodAutoComplete, params, &out); isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	params := SearchParams{Query: req.Query, Safe: req.Safe}
+	if !req.Lang.IsRoot() {
+		params.Lang = req.Lang.String()
+	}
+	if req.Region != (search.RegionCode{}) {
+		params.Region = req.Region.String()
+	}
+	return &searchIter{s: s, req: params}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Req, t.Tok)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, req: t.Req, tok: t.Tok, next: resp.Next, fetched: true, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	req     SearchParams
+	tok     json.RawMessage // plugin token for the current page
+	next    json.RawMessage // plugin token for the next page
+	fetched bool
+
+	page []search.Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || len(it.next) == 0 {
+			it.page = nil
+			return false
+		}
+		it.tok = it.next
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.req, it.tok)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Results
+	it.next = resp.Next
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i]
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Req: it.req,
+		Tok: it.tok,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+// token refers to a result on a page. Pages are requested from the plugin again when the search continues.
+type token struct {
+	Req SearchParams    `json:"req"`
+	Tok json.RawMessage `json:"tok,omitempty"` // plugin token of the page; empty for the first page
+	Off int             `json:"off,omitempty"`
+}
+
+type SearchResp struct {
+	Results []search.Result
+	// Next is a plugin token for the next page, or nil if there are no more results.
+	Next json.RawMessage
+}
+
+// SearchRaw returns a single page of results from the plugin.
+// If the token is set, the search continues from it and the request is ignored.
+func (s *Service) SearchRaw(ctx context.Context, r SearchParams, tok json.RawMessage) (*SearchResp, error) {
+	var (
+		page Page
+		err  error
+	)
+	if len(tok) != 0 {
+		err = s.call(ctx, MethodContinue, ContinueParams{Token: tok}, &page)
+	} else {
+		err = s.call(ctx, MethodSearch, r, &page)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &SearchResp{}
+	if page.HasNext() {
+		out.Next = page.Next
+	}
+	for i := range page.Results {
+		res, err := page.Results[i].toResult()
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %v", s.Path, err)
+		}
+		out.Results = append(out.Results, res)
+	}
+	return out, nil
+}
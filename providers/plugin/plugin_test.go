@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+// examplePath is a path of the reference plugin binary, built once for all tests.
+var examplePath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "metasearch-plugin-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	examplePath = filepath.Join(dir, "example")
+	cmd := exec.Command("go", "build", "-o", examplePath, "./example")
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "cannot build the example plugin:", err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func newExample(t testing.TB) *Service {
+	s, err := New(context.Background(), examplePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func collect(t testing.TB, it search.ResultIterator) []string {
+	ctx := context.Background()
+	defer it.Close()
+	var out []string
+	for it.Next(ctx) {
+		out = append(out, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	s := newExample(t)
+	require.Equal(t, "example", s.ID())
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "http"})
+	require.True(t, it.Next(ctx))
+	r, ok := it.Result().(*search.PackageResult)
+	require.True(t, ok)
+	require.Equal(t, "https://pkg.go.dev/net/http", r.GetURL().String())
+	require.Equal(t, "net/http", r.Name)
+	require.Equal(t, "BSD-3-Clause", r.License)
+	require.Equal(t, time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC), r.Published)
+	it.Close()
+
+	// results span multiple pages
+	all := collect(t, s.Search(ctx, search.Request{Query: "package"}))
+	require.Len(t, all, 6)
+
+	for i := range all {
+		it := s.Search(ctx, search.Request{Query: "package"})
+		for j := 0; j <= i; j++ {
+			require.True(t, it.Next(ctx))
+		}
+		tok := it.Token()
+		it.Close()
+		exp := append([]string(nil), all[i+1:]...)
+		require.Equal(t, exp, collect(t, s.ContinueSearch(ctx, tok)), "after %d", i)
+	}
+}
+
+func TestLocales(t *testing.T) {
+	s := newExample(t)
+	ctx := context.Background()
+	langs, err := s.Languages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []search.Language{{Code: search.MustParseLangCode("en"), Name: "English"}}, langs)
+
+	// the method is not implemented by the plugin
+	regions, err := s.Regions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, regions)
+}
+
+func TestAutoComplete(t *testing.T) {
+	s := newExample(t)
+	got, err := s.AutoComplete(context.Background(), autocomplete.Request{Text: "net/"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"net/http", "net/http/httptest", "net/url"}, got)
+}
+
+func TestRestart(t *testing.T) {
+	defer func(d time.Duration) { RestartDelay = d }(RestartDelay)
+	RestartDelay = time.Hour
+	s := newExample(t)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "crash"})
+	require.False(t, it.Next(ctx))
+	require.Error(t, it.Err())
+
+	// the plugin crashed right after the start, so it is not restarted immediately
+	it = s.Search(ctx, search.Request{Query: "json"})
+	require.False(t, it.Next(ctx))
+	require.Error(t, it.Err())
+
+	RestartDelay = 0
+	require.Equal(t, []string{"encoding/json"}, collect(t, s.Search(ctx, search.Request{Query: "json"})))
+}
+
+func TestTimeout(t *testing.T) {
+	defer func(d time.Duration) { RestartDelay = d }(RestartDelay)
+	RestartDelay = 0
+	s := newExample(t)
+	s.Timeout = 200 * time.Millisecond
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "hang"})
+	require.False(t, it.Next(ctx))
+	require.Error(t, it.Err())
+	require.Contains(t, it.Err().Error(), "timed out")
+
+	// the stuck plugin is restarted
+	require.Equal(t, []string{"encoding/json"}, collect(t, s.Search(ctx, search.Request{Query: "json"})))
+}
+
+func TestStuckInput(t *testing.T) {
+	defer func(d time.Duration) { RestartDelay = d }(RestartDelay)
+	RestartDelay = 0
+	s := newExample(t)
+
+	// the plugin stops reading requests; the caller gives up without killing it
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := s.SearchRaw(ctx, SearchParams{Query: "hang"}, nil)
+	require.Error(t, err)
+
+	// a large request fills the pipe, the call must time out anyway
+	s.Timeout = 200 * time.Millisecond
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.SearchRaw(context.Background(), SearchParams{Query: strings.Repeat("x", 1<<20)}, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the call is blocked")
+	}
+
+	// the stuck plugin is restarted
+	require.Equal(t, []string{"encoding/json"}, collect(t, s.Search(context.Background(), search.Request{Query: "json"})))
+}
@@ -0,0 +1,200 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"sync"
+)
+
+// process is a running plugin.
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *logWriter
+
+	wmu sync.Mutex // serializes requests
+
+	mu      sync.Mutex
+	pending map[int64]chan *Response // nil after the process exits
+	err     error                    // set when the process exits
+	done    chan struct{}            // closed when the process exits
+}
+
+func startProcess(path string, args []string) (*process, error) {
+	cmd := exec.Command(path, args...)
+	stderr := &logWriter{prefix: "plugin " + path + ": "}
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &process{
+		cmd:     cmd,
+		stdin:   stdin,
+		stderr:  stderr,
+		pending: make(map[int64]chan *Response),
+		done:    make(chan struct{}),
+	}
+	go p.read(stdout)
+	return p, nil
+}
+
+// read dispatches responses to pending calls until the process exits.
+func (p *process) read(r io.Reader) {
+	br := bufio.NewReader(r)
+	var err error
+	for {
+		line, rerr := br.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) != 0 {
+			var resp Response
+			if jerr := json.Unmarshal(line, &resp); jerr != nil {
+				log.Printf("%s: invalid response: %v", p.cmd.Path, jerr)
+			} else {
+				p.mu.Lock()
+				ch := p.pending[resp.ID]
+				delete(p.pending, resp.ID)
+				p.mu.Unlock()
+				if ch != nil {
+					ch <- &resp
+				}
+			}
+		}
+		if rerr != nil {
+			if rerr != io.EOF {
+				err = rerr
+			}
+			break
+		}
+	}
+	if werr := p.cmd.Wait(); werr != nil {
+		err = werr
+	}
+	p.stderr.flush()
+	if err == nil {
+		err = errors.New("exited")
+	}
+	p.mu.Lock()
+	p.err = fmt.Errorf("plugin %s: %v", p.cmd.Path, err)
+	p.pending = nil
+	p.mu.Unlock()
+	close(p.done)
+}
+
+// exited checks if the process has exited.
+func (p *process) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *process) exitErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *process) forget(id int64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// call sends a request and waits for the response.
+func (p *process) call(ctx context.Context, req *Request) (*Response, error) {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	line = append(line, '\n')
+	ch := make(chan *Response, 1)
+	p.mu.Lock()
+	if p.pending == nil {
+		p.mu.Unlock()
+		return nil, p.exitErr()
+	}
+	p.pending[req.ID] = ch
+	p.mu.Unlock()
+
+	// the write blocks if the plugin stops reading requests, thus it must respect the context
+	errc := make(chan error, 1)
+	go func() {
+		p.wmu.Lock()
+		defer p.wmu.Unlock()
+		_, err := p.stdin.Write(line)
+		errc <- err
+	}()
+	select {
+	case err = <-errc:
+		if err != nil {
+			p.forget(req.ID)
+			return nil, err
+		}
+	case <-ctx.Done():
+		p.forget(req.ID)
+		return nil, ctx.Err()
+	}
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-p.done:
+		select {
+		case resp := <-ch:
+			return resp, nil
+		default:
+		}
+		return nil, p.exitErr()
+	case <-ctx.Done():
+		p.forget(req.ID)
+		return nil, ctx.Err()
+	}
+}
+
+// logWriter writes lines to the log with a prefix.
+type logWriter struct {
+	prefix string
+	buf    []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		log.Print(w.prefix + string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the last incomplete line, if any.
+func (w *logWriter) flush() {
+	if len(w.buf) != 0 {
+		log.Print(w.prefix + string(w.buf))
+		w.buf = nil
+	}
+}
+
+// kill stops the process.
+func (p *process) kill() {
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+}
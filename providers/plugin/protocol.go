@@ -0,0 +1,231 @@
+// Package plugin implements providers that run as external processes.
+//
+// A plugin is an executable that reads JSON-RPC 2.0 requests from stdin and writes responses to stdout,
+// one JSON object per line. Requests may be sent concurrently, responses are matched by their IDs.
+// Lines written to stderr are passed to the log, prefixed with the plugin path. Methods:
+//
+//	id           -> "name"
+//	languages    -> [{"code": "en-US", "name": "English"}]
+//	regions      -> [{"code": "US", "name": "United States"}]
+//	search       {"query": "...", "lang": "en", "region": "US", "safe": true} -> page
+//	continue     {"token": <next token of the previous page>} -> page
+//	autocomplete {"text": "...", "lang": "en", "region": "US"} -> ["suggestion", ...]
+//
+// A page is {"results": [result, ...], "next": <token>}. The token can be any JSON value, a null token
+// means there are no more results. Only id and search are required; other methods may return
+// the "method not found" error (-32601), which is treated as an empty response.
+//
+// A result is {"type": "link", "url": "...", "title": "...", "desc": "..."} with optional fields
+// specific to the type, see Result. See the example directory for a reference plugin.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+// Method names of the plugin protocol.
+const (
+	MethodID           = "id"
+	MethodLanguages    = "languages"
+	MethodRegions      = "regions"
+	MethodSearch       = "search"
+	MethodContinue     = "continue"
+	MethodAutoComplete = "autocomplete"
+)
+
+// CodeMethodNotFound is a JSON-RPC error code returned for unsupported methods.
+const CodeMethodNotFound = -32601
+
+// Request is a JSON-RPC request sent to the plugin.
+type Request struct {
+	Version string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response returned by the plugin.
+type Response struct {
+	Version string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("plugin error %d: %s", e.Code, e.Message)
+}
+
+// Locale is a language or a region supported by the plugin.
+type Locale struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+// SearchParams are parameters of the search method.
+type SearchParams struct {
+	Query  string `json:"query"`
+	Lang   string `json:"lang,omitempty"`
+	Region string `json:"region,omitempty"`
+	Safe   bool   `json:"safe,omitempty"`
+}
+
+// ContinueParams are parameters of the continue method.
+type ContinueParams struct {
+	Token json.RawMessage `json:"token"`
+}
+
+// CompleteParams are parameters of the autocomplete method.
+type CompleteParams struct {
+	Text   string `json:"text"`
+	Lang   string `json:"lang,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+// Page is a page of search results.
+type Page struct {
+	Results []Result `json:"results"`
+	// Next is a token for the next page. It is null if there are no more results.
+	Next json.RawMessage `json:"next,omitempty"`
+}
+
+// HasNext checks if there are more pages.
+func (p *Page) HasNext() bool {
+	return len(p.Next) != 0 && string(p.Next) != "null"
+}
+
+// Result is a search result. Type selects the result type, other fields are used if they apply to it:
+//
+//	link       - url, title, desc
+//	image      - url of the image, page_url, thumbnail, width, height
+//	video      - thumbnail
+//	entity     - thumbnail, kind (entity type), category, attributes
+//	package    - name, version, license, downloads, repository, date (published)
+//	repo       - name, stars, forks, language, tags (topics), date (updated)
+//	paper      - authors, tags (categories), pdf, date (published)
+//	discussion - link, forum, author, points, comments, date (created)
+//	qa         - score, answers, accepted, tags, date (updated)
+//	answer     - kind, value
+type Result struct {
+	Type  string `json:"type,omitempty"` // "link" by default
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Desc  string `json:"desc,omitempty"`
+
+	Thumbnail string `json:"thumbnail,omitempty"`
+	PageURL   string `json:"page_url,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+
+	Name       string            `json:"name,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Value      string            `json:"value,omitempty"`
+	Category   string            `json:"category,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	License    string            `json:"license,omitempty"`
+	Repository string            `json:"repository,omitempty"`
+	Language   string            `json:"language,omitempty"`
+	Authors    []string          `json:"authors,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	PDF        string            `json:"pdf,omitempty"`
+	Link       string            `json:"link,omitempty"`
+	Forum      string            `json:"forum,omitempty"`
+	Author     string            `json:"author,omitempty"`
+	Downloads  int               `json:"downloads,omitempty"`
+	Stars      int               `json:"stars,omitempty"`
+	Forks      int               `json:"forks,omitempty"`
+	Points     int               `json:"points,omitempty"`
+	Score      int               `json:"score,omitempty"`
+	Answers    int               `json:"answers,omitempty"`
+	Comments   int               `json:"comments,omitempty"`
+	Accepted   bool              `json:"accepted,omitempty"`
+	Date       *time.Time        `json:"date,omitempty"`
+}
+
+// toResult converts the result to one of search.Result types.
+func (r *Result) toResult() (search.Result, error) {
+	var err error
+	parseURL := func(s string) *url.URL {
+		if s == "" || err != nil {
+			return nil
+		}
+		u, perr := url.Parse(s)
+		if perr != nil {
+			err = perr
+		}
+		return u
+	}
+	u, thumbURL, pageURL, repo, pdf, link := parseURL(r.URL), parseURL(r.Thumbnail),
+		parseURL(r.PageURL), parseURL(r.Repository), parseURL(r.PDF), parseURL(r.Link)
+	if err != nil {
+		return nil, err
+	}
+	lr := search.LinkResult{Title: r.Title, Desc: r.Desc}
+	if u != nil {
+		lr.URL = *u
+	}
+	var thumb *search.Image
+	if thumbURL != nil {
+		thumb = &search.Image{URL: *thumbURL}
+	}
+	var date time.Time
+	if r.Date != nil {
+		date = *r.Date
+	}
+	switch r.Type {
+	case "", "link":
+		return &lr, nil
+	case "image":
+		return &search.ImageResult{
+			Image:     search.Image{URL: lr.URL, Width: r.Width, Height: r.Height},
+			Title:     r.Title,
+			Desc:      r.Desc,
+			PageURL:   pageURL,
+			Thumbnail: thumb,
+		}, nil
+	case "video":
+		return &search.VideoResult{LinkResult: lr, Thumbnail: thumb}, nil
+	case "entity":
+		return &search.EntityResult{
+			LinkResult: lr, Type: r.Kind, Category: r.Category, Image: thumb, Attributes: r.Attributes,
+		}, nil
+	case "package":
+		return &search.PackageResult{
+			LinkResult: lr, Name: r.Name, Version: r.Version, License: r.License,
+			Downloads: r.Downloads, Repository: repo, Published: date,
+		}, nil
+	case "repo":
+		return &search.RepoResult{
+			LinkResult: lr, Name: r.Name, Stars: r.Stars, Forks: r.Forks,
+			Language: r.Language, Topics: r.Tags, Updated: date,
+		}, nil
+	case "paper":
+		return &search.PaperResult{
+			LinkResult: lr, Authors: r.Authors, Categories: r.Tags, Published: date, PDF: pdf,
+		}, nil
+	case "discussion":
+		return &search.DiscussionResult{
+			LinkResult: lr, Link: link, Forum: r.Forum, Author: r.Author,
+			Points: r.Points, Comments: r.Comments, Created: date,
+		}, nil
+	case "qa":
+		return &search.QAResult{
+			LinkResult: lr, Score: r.Score, Answers: r.Answers, Accepted: r.Accepted, Tags: r.Tags, Updated: date,
+		}, nil
+	case "answer":
+		return &search.AnswerResult{LinkResult: lr, Kind: r.Kind, Value: r.Value}, nil
+	}
+	return nil, fmt.Errorf("unsupported result type: %q", r.Type)
+}
@@ -0,0 +1,59 @@
+package providertest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// NewServer starts a test server and returns an HTTP client that sends all requests to it,
+// regardless of the host in the request URL. The original host is preserved in the Host header.
+func NewServer(t testing.TB, h http.Handler) (*httptest.Server, *http.Client) {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := srv.Client()
+	cli.Transport = &redirect{rt: cli.Transport, host: u.Host}
+	return srv, cli
+}
+
+type redirect struct {
+	rt   http.RoundTripper
+	host string
+}
+
+func (t *redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+	req.URL.Scheme = "http"
+	req.URL.Host = t.host
+	return t.rt.RoundTrip(req)
+}
+
+// ServeFile returns a handler that responds with the content of a given file.
+func ServeFile(t testing.TB, path, contentType string) http.HandlerFunc {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	}
+}
+
+// MustURL parses a URL and panics on error.
+func MustURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
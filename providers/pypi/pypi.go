@@ -0,0 +1,457 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName    = "pypi"
+	baseURL     = "https://pypi.org"
+	indexPath   = "/simple/"
+	projectURL  = baseURL + "/project/"
+	acceptIndex = "application/vnd.pypi.simple.v1+json"
+
+	// EnvIndex enables matching queries against all project names, if set to "true".
+	// It requires downloading the list of all projects, thus only exact names are looked up by default.
+	EnvIndex = "METAS_PYPI_INDEX"
+)
+
+var (
+	DefaultPageSize = 20
+	// IndexTTL controls how often the list of all projects is refreshed.
+	IndexTTL = time.Hour
+	// IndexTimeout limits the download of the list of all projects.
+	IndexTimeout = 2 * time.Minute
+	// IndexWait is how long a search waits for the list of projects when it is loaded for the first time.
+	// The download continues in the background if the search gives up.
+	IndexWait = 5 * time.Second
+	// MaxConcurrent limits the number of concurrent metadata requests.
+	MaxConcurrent = 4
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		s := New()
+		s.UseIndex = os.Getenv(EnvIndex) == "true"
+		return s, nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+// Service searches PyPI projects by name. PyPI has no search API, thus only the project with
+// the exact name is looked up, unless UseIndex is set.
+//
+// Results do not report downloads: PyPI does not expose download counts in its API.
+type Service struct {
+	providers.HTTPClient
+
+	// UseIndex enables matching queries against all project names. The simple index is downloaded
+	// in the background, cached and matched locally.
+	UseIndex bool
+
+	mu      sync.Mutex
+	names   []string // normalized project names
+	fetched time.Time
+	loading chan struct{} // closed when the index is loaded; nil if not loading
+	loadErr error
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, total: resp.Total, fetched: true, page: resp.Projects, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	total   int
+	fetched bool
+
+	page []*Project
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.cur.Offset+DefaultPageSize >= it.total {
+			it.page = nil
+			return false
+		}
+		it.cur.Offset += DefaultPageSize
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Projects
+	it.total = resp.Total
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query  string `json:"q"`
+	Offset int    `json:"off,omitempty"`
+}
+
+type SearchResp struct {
+	Projects []*Project
+	Total    int // total number of matching names
+}
+
+var (
+	reSep = regexp.MustCompile(`[-_.]+`)
+	// reName matches valid normalized project names.
+	reName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+// Normalize returns a project name normalized according to PEP 503.
+func Normalize(name string) string {
+	return reSep.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+type simpleIndex struct {
+	Projects []struct {
+		Name string `json:"name"`
+	} `json:"projects"`
+}
+
+// index returns normalized names of all projects. A stale copy is returned while the index is refreshed
+// in the background. If there is no copy yet, it waits for the download for at most IndexWait.
+func (s *Service) index(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	names := s.names
+	if names != nil && time.Since(s.fetched) < IndexTTL {
+		s.mu.Unlock()
+		return names, nil
+	}
+	if s.loading == nil {
+		s.loading = make(chan struct{})
+		go s.load(s.loading)
+	}
+	loading := s.loading
+	s.mu.Unlock()
+	if names != nil {
+		return names, nil
+	}
+	t := time.NewTimer(IndexWait)
+	defer t.Stop()
+	select {
+	case <-loading:
+	case <-t.C:
+		return nil, fmt.Errorf("pypi: the index is still loading")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.names == nil {
+		return nil, s.loadErr
+	}
+	return s.names, nil
+}
+
+// load downloads the index and closes the channel when done.
+func (s *Service) load(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), IndexTimeout)
+	defer cancel()
+	names, err := s.fetchIndex(ctx)
+	s.mu.Lock()
+	if err == nil {
+		s.names, s.fetched = names, time.Now()
+	} else if s.names != nil {
+		log.Printf("pypi: cannot refresh the index: %v", err)
+	}
+	s.loadErr = err
+	s.loading = nil
+	s.mu.Unlock()
+	close(done)
+}
+
+// fetchIndex downloads normalized names of all projects.
+func (s *Service) fetchIndex(ctx context.Context) ([]string, error) {
+	req, err := s.GetRequest(indexPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", acceptIndex)
+	var out simpleIndex
+	if _, err := s.DoJSON(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(out.Projects))
+	for _, p := range out.Projects {
+		names = append(names, Normalize(p.Name))
+	}
+	return names, nil
+}
+
+// match returns names containing the query. Exact matches go first, followed by prefix matches
+// and the rest. Shorter names are ranked higher within each group.
+func match(names []string, q string) []string {
+	type scored struct {
+		name string
+		rank int
+	}
+	var out []scored
+	for _, name := range names {
+		i := strings.Index(name, q)
+		if i < 0 {
+			continue
+		}
+		rank := 2
+		if name == q {
+			rank = 0
+		} else if i == 0 {
+			rank = 1
+		}
+		out = append(out, scored{name: name, rank: rank})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if len(a.name) != len(b.name) {
+			return len(a.name) < len(b.name)
+		}
+		return a.name < b.name
+	})
+	res := make([]string, 0, len(out))
+	for _, s := range out {
+		res = append(res, s.name)
+	}
+	return res
+}
+
+// SearchRaw matches the query against project names and fetches metadata for a single page of results.
+// Without UseIndex, only the project with the exact name is returned.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	q := Normalize(strings.TrimSpace(r.Query))
+	if q == "" {
+		return &SearchResp{}, nil
+	}
+	names := []string{q}
+	if !s.UseIndex && !reName.MatchString(q) {
+		return &SearchResp{}, nil
+	}
+	if s.UseIndex {
+		all, err := s.index(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = match(all, q)
+	}
+	resp := &SearchResp{Total: len(names)}
+	if r.Offset >= len(names) {
+		return resp, nil
+	}
+	names = names[r.Offset:]
+	if len(names) > DefaultPageSize {
+		names = names[:DefaultPageSize]
+	}
+	projects := make([]*Project, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrent)
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-sem; wg.Done() }()
+			projects[i], errs[i] = s.Project(ctx, name)
+		}(i, name)
+	}
+	wg.Wait()
+	for i, p := range projects {
+		if err := errs[i]; isNotFound(err) {
+			continue // no such project, or it was removed since the index was fetched
+		} else if err != nil {
+			return nil, err
+		}
+		resp.Projects = append(resp.Projects, p)
+	}
+	return resp, nil
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(*providers.ErrHTTPStatus)
+	return ok && e.Code == http.StatusNotFound
+}
+
+// Project is a subset of the project metadata returned by the JSON API.
+type Project struct {
+	Info struct {
+		Name              string            `json:"name"`
+		Version           string            `json:"version"`
+		Summary           string            `json:"summary"`
+		License           string            `json:"license"`
+		LicenseExpression string            `json:"license_expression"`
+		HomePage          string            `json:"home_page"`
+		ProjectURLs       map[string]string `json:"project_urls"`
+	} `json:"info"`
+	URLs []struct {
+		Uploaded time.Time `json:"upload_time_iso_8601"`
+	} `json:"urls"`
+}
+
+// Project fetches metadata for the latest release of a project.
+func (s *Service) Project(ctx context.Context, name string) (*Project, error) {
+	var out Project
+	if err := s.GetJSON(ctx, "/pypi/"+url.PathEscape(name)+"/json", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// repoKeys lists project URL labels that may point to the source repository, in order of preference.
+var repoKeys = []string{"source", "source code", "repository", "code", "github", "homepage"}
+
+func (p *Project) repository() string {
+	urls := make(map[string]string, len(p.Info.ProjectURLs))
+	for k, v := range p.Info.ProjectURLs {
+		urls[strings.ToLower(k)] = v
+	}
+	for _, k := range repoKeys {
+		if v := urls[k]; v != "" {
+			return v
+		}
+	}
+	return p.Info.HomePage
+}
+
+func (p *Project) toResult() (*search.PackageResult, error) {
+	u, err := url.Parse(projectURL + Normalize(p.Info.Name) + "/")
+	if err != nil {
+		return nil, err
+	}
+	r := &search.PackageResult{
+		LinkResult: search.LinkResult{URL: *u, Title: p.Info.Name, Desc: p.Info.Summary},
+		Name:       p.Info.Name,
+		Version:    p.Info.Version,
+		License:    p.Info.LicenseExpression,
+	}
+	if r.License == "" && !strings.Contains(p.Info.License, "\n") {
+		// older projects sometimes put the whole license text here
+		r.License = p.Info.License
+	}
+	if repo := p.repository(); repo != "" {
+		if r.Repository, err = url.Parse(repo); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.URLs) != 0 {
+		r.Published = p.URLs[0].Uploaded.UTC()
+	}
+	return r, nil
+}
@@ -0,0 +1,168 @@
+package pypi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "requests-toolbelt", Normalize("Requests_Toolbelt"))
+	require.Equal(t, "zope-interface", Normalize("zope.__interface"))
+}
+
+func TestSearch(t *testing.T) {
+	defer func(n int) { DefaultPageSize = n }(DefaultPageSize)
+	DefaultPageSize = 2
+
+	var (
+		mu      sync.Mutex
+		indexed int
+	)
+	index := providertest.ServeFile(t, "testdata/simple.json", acceptIndex)
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "pypi.org", r.Host)
+		if r.URL.Path == indexPath {
+			require.Equal(t, acceptIndex, r.Header.Get("Accept"))
+			mu.Lock()
+			indexed++
+			mu.Unlock()
+			index(w, r)
+			return
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/pypi/"), "/json")
+		if name == "requests-gone" {
+			http.NotFound(w, r)
+			return
+		}
+		providertest.ServeFile(t, "testdata/"+name+".json", "application/json")(w, r)
+	}))
+	s := New()
+	s.UseIndex = true
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "Requests"})
+	defer it.Close()
+	var (
+		got []search.Result
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+		if len(got) == 2 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 1, indexed, "index should be cached")
+
+	var names []string
+	for _, r := range got {
+		names = append(names, r.GetTitle())
+	}
+	require.Equal(t, []string{"requests", "requests-oauthlib", "requests-toolbelt", "grequests"}, names)
+	require.Equal(t, &search.PackageResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://pypi.org/project/requests/"),
+			Title: "requests",
+			Desc:  "Python HTTP for Humans.",
+		},
+		Name:       "requests",
+		Version:    "2.32.3",
+		License:    "Apache-2.0",
+		Repository: providertest.MustURL("https://github.com/psf/requests"),
+		Published:  time.Date(2024, 5, 29, 15, 37, 47, 27801000, time.UTC),
+	}, got[0])
+
+	oauth := got[1].(*search.PackageResult)
+	require.Empty(t, oauth.License)
+	require.Equal(t, "https://github.com/requests/requests-oauthlib", oauth.Repository.String())
+
+	belt := got[2].(*search.PackageResult)
+	require.Equal(t, "Apache-2.0", belt.License)
+	require.Equal(t, "https://github.com/requests/toolbelt", belt.Repository.String())
+	require.True(t, belt.Published.IsZero())
+
+	it = s.ContinueSearch(ctx, tok)
+	defer it.Close()
+	names = nil
+	for it.Next(ctx) {
+		names = append(names, it.Result().GetTitle())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"requests-toolbelt", "grequests"}, names)
+}
+
+func TestIndexLoading(t *testing.T) {
+	defer func(d time.Duration) { IndexWait = d }(IndexWait)
+	IndexWait = 50 * time.Millisecond
+
+	release := make(chan struct{})
+	index := providertest.ServeFile(t, "testdata/simple.json", acceptIndex)
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == indexPath {
+			<-release
+			index(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	s := New()
+	s.UseIndex = true
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	// searches do not wait for the slow download
+	start := time.Now()
+	_, err := s.SearchRaw(ctx, SearchReq{Query: "requests"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "loading")
+	require.True(t, time.Since(start) < time.Second)
+
+	// the download continues in the background
+	close(release)
+	IndexWait = 5 * time.Second
+	names, err := s.index(ctx)
+	require.NoError(t, err)
+	require.Contains(t, names, "requests")
+}
+
+func TestSearchExact(t *testing.T) {
+	var paths []string
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/pypi/requests-toolbelt/json" {
+			providertest.ServeFile(t, "testdata/requests-toolbelt.json", "application/json")(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	resp, err := s.SearchRaw(ctx, SearchReq{Query: "Requests_Toolbelt"})
+	require.NoError(t, err)
+	require.Len(t, resp.Projects, 1)
+	require.Equal(t, "requests-toolbelt", resp.Projects[0].Info.Name)
+
+	resp, err = s.SearchRaw(ctx, SearchReq{Query: "missing"})
+	require.NoError(t, err)
+	require.Empty(t, resp.Projects)
+
+	// not a project name
+	resp, err = s.SearchRaw(ctx, SearchReq{Query: "http client"})
+	require.NoError(t, err)
+	require.Empty(t, resp.Projects)
+
+	// the index is never downloaded
+	require.Equal(t, []string{"/pypi/requests-toolbelt/json", "/pypi/missing/json"}, paths)
+}
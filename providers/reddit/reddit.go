@@ -0,0 +1,306 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "reddit"
+	baseURL    = "https://www.reddit.com"
+	searchPath = "/search.json"
+	infoPath   = "/api/info.json"
+)
+
+var (
+	DefaultLimit = 25
+	maxDescLen   = 300
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var (
+	_ search.Service          = (*Service)(nil)
+	_ search.DiscussionFinder = (*Service)(nil)
+)
+
+// New creates a Reddit provider that uses public JSON endpoints.
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, SafeSearch: req.Safe}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, after: resp.After, fetched: true, page: resp.Posts, i: t.Off}
+}
+
+// Discussions returns posts that link to a given URL.
+func (s *Service) Discussions(ctx context.Context, u *url.URL) ([]*search.DiscussionResult, error) {
+	params := make(url.Values)
+	params.Set("url", u.String())
+	var resp listing
+	if err := s.get(ctx, infoPath, params, &resp); err != nil {
+		return nil, err
+	}
+	out := make([]*search.DiscussionResult, 0, len(resp.Data.Children))
+	for _, c := range resp.Data.Children {
+		r, err := c.Data.toResult()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	after   string
+	fetched bool
+
+	page []Post
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.after == "" {
+			it.page = nil
+			return false
+		}
+		it.cur.After, it.after = it.after, ""
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Posts
+	it.after = resp.After
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query      string `json:"q"`
+	After      string `json:"after,omitempty"`
+	SafeSearch bool   `json:"safe"`
+}
+
+// Post is a link or a text post.
+type Post struct {
+	Name        string  `json:"name"` // fullname, e.g. "t3_15l8ztf"
+	Title       string  `json:"title"`
+	Permalink   string  `json:"permalink"`
+	URL         string  `json:"url"`
+	IsSelf      bool    `json:"is_self"`
+	SelfText    string  `json:"selftext"`
+	Author      string  `json:"author"`
+	Subreddit   string  `json:"subreddit_name_prefixed"`
+	Score       int     `json:"score"`
+	NumComments int     `json:"num_comments"`
+	Created     float64 `json:"created_utc"`
+	Over18      bool    `json:"over_18"`
+}
+
+// excerpt returns the first paragraph of the text, truncated to a given length.
+func excerpt(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n\n"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
+
+func (p *Post) toResult() (*search.DiscussionResult, error) {
+	u, err := url.Parse(baseURL + p.Permalink)
+	if err != nil {
+		return nil, err
+	}
+	r := &search.DiscussionResult{
+		LinkResult: search.LinkResult{URL: *u, Title: p.Title},
+		Forum:      p.Subreddit,
+		Author:     p.Author,
+		Points:     p.Score,
+		Comments:   p.NumComments,
+		Created:    time.Unix(int64(p.Created), 0).UTC(),
+	}
+	if p.IsSelf {
+		r.Desc = excerpt(p.SelfText, maxDescLen)
+	} else if p.URL != "" {
+		if r.Link, err = url.Parse(p.URL); err != nil {
+			return nil, err
+		}
+		r.Desc = r.Link.String()
+	}
+	return r, nil
+}
+
+type listing struct {
+	Data struct {
+		After    string `json:"after"`
+		Children []struct {
+			Kind string `json:"kind"`
+			Data Post   `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+type SearchResp struct {
+	Posts []Post
+	// After is a fullname of the last post, used to request the next page.
+	After string
+}
+
+// get sends a request to a JSON endpoint. Responses are requested without HTML escaping.
+func (s *Service) get(ctx context.Context, path string, params url.Values, dst interface{}) error {
+	params.Set("raw_json", "1")
+	req, err := s.GetRequest(path, params)
+	if err != nil {
+		return err
+	}
+	// Reddit blocks requests with generic user agents
+	req.Header.Set("User-Agent", providers.UserAgent)
+	_, err = s.DoJSON(ctx, req, dst)
+	return err
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("type", "link")
+	params.Set("sort", "relevance")
+	params.Set("limit", strconv.Itoa(DefaultLimit))
+	if r.After != "" {
+		params.Set("after", r.After)
+	}
+	if r.SafeSearch {
+		params.Set("include_over_18", "off")
+	} else {
+		params.Set("include_over_18", "on")
+	}
+	var resp listing
+	if err := s.get(ctx, searchPath, params, &resp); err != nil {
+		return nil, err
+	}
+	out := &SearchResp{After: resp.Data.After}
+	for _, c := range resp.Data.Children {
+		if c.Kind != "t3" || (r.SafeSearch && c.Data.Over18) {
+			continue
+		}
+		out.Posts = append(out.Posts, c.Data)
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package reddit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page0 := providertest.ServeFile(t, "testdata/search_0.json", "application/json")
+	page1 := providertest.ServeFile(t, "testdata/search_1.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "www.reddit.com", r.Host)
+		require.Equal(t, searchPath, r.URL.Path)
+		require.Equal(t, providers.UserAgent, r.Header.Get("User-Agent"))
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("after") == "" {
+			page0(w, r)
+		} else {
+			page1(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "go 1.21", Safe: true})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, "off", reqs[0].Get("include_over_18"))
+	require.Equal(t, "t3_15lhpac", reqs[1].Get("after"))
+
+	require.Len(t, got, 3)
+	require.Equal(t, &search.DiscussionResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://www.reddit.com/r/golang/comments/15l8ztf/go_121_is_released/"),
+			Title: "Go 1.21 is released & it's great",
+			Desc:  "https://go.dev/blog/go1.21",
+		},
+		Link:     providertest.MustURL("https://go.dev/blog/go1.21"),
+		Forum:    "r/golang",
+		Author:   "gopher",
+		Points:   523,
+		Comments: 97,
+		Created:  time.Unix(1691506900, 0).UTC(),
+	}, got[0])
+	self := got[1].(*search.DiscussionResult)
+	require.Nil(t, self.Link)
+	require.Equal(t, "Generics improvements, new min/max builtins.", self.Desc)
+	require.Equal(t, "Go 1.21 release notes", got[2].GetTitle())
+}
+
+func TestDiscussions(t *testing.T) {
+	serve := providertest.ServeFile(t, "testdata/info.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, infoPath, r.URL.Path)
+		require.Equal(t, "https://go.dev/blog/go1.21", r.URL.Query().Get("url"))
+		serve(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+
+	got, err := s.Discussions(context.Background(), providertest.MustURL("https://go.dev/blog/go1.21"))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "r/golang", got[0].Forum)
+	require.Equal(t, 97, got[0].Comments)
+}
@@ -8,14 +8,25 @@ import (
 
 type Provider = base.Provider
 
+// ProviderFunc creates a new provider. It may return a nil provider if the provider is not configured.
 type ProviderFunc func(ctx context.Context) (Provider, error)
 
-var registry = make(map[string]ProviderFunc)
+var (
+	registry = make(map[string]ProviderFunc)
+	private  = make(map[string]bool)
+)
 
 func Register(name string, fnc ProviderFunc) {
 	registry[name] = fnc
 }
 
+// RegisterPrivate registers a provider that exposes local data of the user, such as files or browsing history.
+// Private providers are not served to other nodes, unless it is allowed explicitly.
+func RegisterPrivate(name string, fnc ProviderFunc) {
+	Register(name, fnc)
+	private[name] = true
+}
+
 func List() []ProviderFunc {
 	var arr []ProviderFunc
 	for _, fnc := range registry {
@@ -23,3 +34,14 @@ func List() []ProviderFunc {
 	}
 	return arr
 }
+
+// ListPublic is like List, but skips private providers.
+func ListPublic() []ProviderFunc {
+	var arr []ProviderFunc
+	for name, fnc := range registry {
+		if !private[name] {
+			arr = append(arr, fnc)
+		}
+	}
+	return arr
+}
@@ -0,0 +1,269 @@
+// Package remote implements a provider that federates other metasearch instances via their HTTP API.
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+	"github.com/dennwc/metasearch/server"
+)
+
+const (
+	provName = "remote"
+
+	// EnvNodes is a list of base URLs of remote metasearch instances.
+	EnvNodes = "METAS_REMOTES"
+)
+
+var DefaultPageSize = 20
+
+func init() {
+	// each node is a separate provider, so the engine merges their results as usual
+	for _, addr := range providers.EnvList(EnvNodes) {
+		addr := addr
+		providers.Register(provName+":"+addr, func(ctx context.Context) (providers.Provider, error) {
+			return New(addr), nil
+		})
+	}
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// New creates a provider for a metasearch instance with a given base URL.
+func New(addr string) *Service {
+	addr = strings.TrimSuffix(addr, "/")
+	name := addr
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(addr),
+		Name:       provName + ":" + name,
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+	// Name is used as the provider ID. It defaults to "remote:<host>".
+	Name string
+}
+
+func (s *Service) ID() string {
+	return s.Name
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil // the remote engine localizes requests for its providers
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+// get sends an API request, passing the list of nodes that already handled it.
+func (s *Service) get(ctx context.Context, path string, params url.Values, dst interface{}) error {
+	req, err := s.GetRequest(path, params)
+	if err != nil {
+		return err
+	}
+	if via := server.Via(ctx); len(via) != 0 {
+		req.Header.Set(server.HeaderVia, strings.Join(via, ", "))
+	}
+	_, err = s.DoJSON(ctx, req, dst)
+	return err
+}
+
+func (r SearchReq) params() url.Values {
+	params := url.Values{"q": {r.Query}}
+	if r.Lang != "" {
+		params.Set("lang", r.Lang)
+	}
+	if r.Region != "" {
+		params.Set("region", r.Region)
+	}
+	if r.Safe {
+		params.Set("safe", "true")
+	}
+	return params
+}
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	sreq := newSearchReq(search.Request{Query: req.Text, Lang: req.Lang, Region: req.Region})
+	var out []string
+	if err := s.get(ctx, server.CompletePath, sreq.params(), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func newSearchReq(req search.Request) SearchReq {
+	r := SearchReq{Query: req.Query, Safe: req.Safe}
+	if !req.Lang.IsRoot() {
+		r.Lang = req.Lang.String()
+	}
+	if req.Region != (search.RegionCode{}) {
+		r.Region = req.Region.String()
+	}
+	return r
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, req: newSearchReq(req)}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Req, t.Tok)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, req: t.Req, tok: t.Tok, next: resp.Token, fetched: true, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	req     SearchReq
+	tok     search.Token // remote token for the current page
+	next    search.Token // remote token for the next page
+	fetched bool
+
+	page []search.Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || len(it.next) == 0 {
+			it.page = nil
+			return false
+		}
+		it.tok = it.next
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.req, it.tok)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Results
+	it.next = resp.Token
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i]
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Req: it.req,
+		Tok: it.tok,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+// token refers to a result on a page. Remote pages are fetched again when the search continues.
+type token struct {
+	Req SearchReq    `json:"req"`
+	Tok search.Token `json:"tok,omitempty"` // remote token of the page; empty for the first page
+	Off int          `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query  string `json:"q"`
+	Lang   string `json:"lang,omitempty"`
+	Region string `json:"region,omitempty"`
+	Safe   bool   `json:"safe,omitempty"`
+}
+
+type SearchResp struct {
+	Node    string
+	Results []search.Result
+	// Token is a remote token for the next page.
+	Token search.Token
+}
+
+// SearchRaw returns a single page of results from the remote instance.
+// If the token is set, the search continues from it and the request is ignored.
+// Results are returned as search.FederatedResult.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq, tok search.Token) (*SearchResp, error) {
+	params := r.params()
+	if len(tok) != 0 {
+		params = url.Values{"token": {base64.RawURLEncoding.EncodeToString(tok)}}
+	}
+	params.Set("n", strconv.Itoa(DefaultPageSize))
+	var resp server.SearchResponse
+	if err := s.get(ctx, server.SearchPath, params, &resp); err != nil {
+		return nil, err
+	}
+	out := &SearchResp{Node: resp.Node, Token: resp.Token}
+	for i := range resp.Results {
+		r, err := server.DecodeResult(&resp.Results[i])
+		if err != nil {
+			return nil, err
+		}
+		out.Results = append(out.Results, &search.FederatedResult{
+			Result: r,
+			Node:   resp.Node,
+			Source: resp.Results[i].Source,
+		})
+	}
+	return out, nil
+}
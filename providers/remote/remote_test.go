@@ -0,0 +1,200 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dennwc/metasearch"
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/base"
+	"github.com/dennwc/metasearch/search"
+	"github.com/dennwc/metasearch/server"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ search.Service       = (*listService)(nil)
+	_ autocomplete.Service = (*listService)(nil)
+)
+
+// listService returns a fixed list of results, regardless of the query.
+type listService struct {
+	id      string
+	results []string
+}
+
+func (s *listService) ID() string {
+	return s.id
+}
+
+func (s *listService) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *listService) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *listService) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	var out []string
+	for _, v := range s.results {
+		if strings.HasPrefix(v, req.Text) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (s *listService) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &listIter{s: s, i: -1}
+}
+
+func (s *listService) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	i, err := strconv.Atoi(string(tok))
+	if err != nil {
+		return &listIter{err: err}
+	}
+	return &listIter{s: s, i: i}
+}
+
+type listIter struct {
+	search.Empty
+	s   *listService
+	i   int
+	err error
+}
+
+func (it *listIter) Buffered() int {
+	if it.s == nil {
+		return 0
+	}
+	return len(it.s.results) - (it.i + 1)
+}
+
+func (it *listIter) Next(ctx context.Context) bool {
+	if it.Buffered() == 0 {
+		return false
+	}
+	it.i++
+	return true
+}
+
+func (it *listIter) Err() error {
+	return it.err
+}
+
+func (it *listIter) Result() search.Result {
+	v := it.s.results[it.i]
+	return &search.LinkResult{URL: url.URL{Scheme: "https", Host: it.s.id, Path: "/" + v}, Title: v}
+}
+
+func (it *listIter) Token() search.Token {
+	return search.Token(strconv.Itoa(it.i))
+}
+
+// node is a metasearch instance served by an in-process HTTP server.
+type node struct {
+	srv *httptest.Server
+	h   http.Handler
+}
+
+func newNode(t testing.TB) *node {
+	n := &node{}
+	n.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(n.srv.Close)
+	return n
+}
+
+// start starts serving an engine with given providers on the node.
+func (n *node) start(t testing.TB, id string, provs ...search.Service) {
+	var arr []base.Provider
+	for _, p := range provs {
+		arr = append(arr, p)
+	}
+	e, err := metasearch.NewEngine(context.Background(), arr...)
+	require.NoError(t, err)
+	n.h = server.New(id, e)
+}
+
+func collect(t testing.TB, it search.ResultIterator) []*search.FederatedResult {
+	ctx := context.Background()
+	defer it.Close()
+	var out []*search.FederatedResult
+	for it.Next(ctx) {
+		out = append(out, it.Result().(*search.FederatedResult))
+	}
+	require.NoError(t, it.Err())
+	return out
+}
+
+func titles(arr []*search.FederatedResult) []string {
+	var out []string
+	for _, r := range arr {
+		out = append(out, r.GetTitle())
+	}
+	return out
+}
+
+func TestFederation(t *testing.T) {
+	defer func(n int) { DefaultPageSize = n }(DefaultPageSize)
+	DefaultPageSize = 2
+
+	a, b := newNode(t), newNode(t)
+	b.start(t, "b", &listService{id: "web-b", results: []string{"b1", "b2", "b3"}})
+	remoteB := New(b.srv.URL)
+	a.start(t, "a", remoteB, &listService{id: "web-a", results: []string{"a1"}})
+
+	ctx := context.Background()
+	s := New(a.srv.URL)
+	got := collect(t, s.Search(ctx, search.Request{Query: "test"}))
+	require.ElementsMatch(t, []string{"a1", "b1", "b2", "b3"}, titles(got))
+	for _, r := range got {
+		require.Equal(t, "a", r.Node)
+		if strings.HasPrefix(r.GetTitle(), "b") {
+			// provenance includes the provider on the node that federates the result
+			require.Equal(t, []string{remoteB.ID(), "web-b"}, r.Source)
+			require.Equal(t, "https://web-b/"+r.GetTitle(), r.GetURL().String())
+		} else {
+			require.Equal(t, []string{"web-a"}, r.Source)
+		}
+	}
+
+	// continuation tokens of all nodes are passed through
+	for i := range got {
+		it := s.Search(ctx, search.Request{Query: "test"})
+		for j := 0; j <= i; j++ {
+			require.True(t, it.Next(ctx))
+		}
+		tok := it.Token()
+		require.NoError(t, it.Err())
+		it.Close()
+
+		rest := collect(t, s.ContinueSearch(ctx, tok))
+		require.Equal(t, titles(got[i+1:]), titles(rest), "after %d", i)
+	}
+}
+
+func TestFederationLoop(t *testing.T) {
+	a, b := newNode(t), newNode(t)
+	a.start(t, "a", New(b.srv.URL), &listService{id: "web-a", results: []string{"a1"}})
+	b.start(t, "b", New(a.srv.URL), &listService{id: "web-b", results: []string{"b1"}})
+
+	// each node sees results of the other one exactly once
+	got := collect(t, New(a.srv.URL).Search(context.Background(), search.Request{Query: "test"}))
+	require.ElementsMatch(t, []string{"a1", "b1"}, titles(got))
+}
+
+func TestAutoComplete(t *testing.T) {
+	a := newNode(t)
+	a.start(t, "a", &listService{id: "web-a", results: []string{"go", "rust", "golang"}})
+	got, err := New(a.srv.URL).AutoComplete(context.Background(), autocomplete.Request{Text: "go"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go", "golang"}, got)
+}
@@ -0,0 +1,216 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config describes a search provider that scrapes HTML results using CSS selectors.
+//
+// Values of URL parameters may contain placeholders:
+//
+//	{query}   - search query
+//	{lang}    - base language code, e.g. "de"
+//	{locale}  - full language code, e.g. "de-AT"
+//	{region}  - region code, e.g. "AT"
+//	{country} - lower-case region code, e.g. "at"
+//	{offset}  - zero-based offset of the first result (see Pagination)
+//	{page}    - page number (see Pagination)
+type Config struct {
+	// Name of the provider. It is registered with a "scraper:" prefix, which is also a part of its ID.
+	Name string `json:"name"`
+	// URL of the search page.
+	URL string `json:"url"`
+	// Method is an HTTP method to use: GET (default) or POST.
+	Method string `json:"method,omitempty"`
+	// Params is a list of query or form parameters.
+	Params map[string]string `json:"params"`
+	// SafeParams are added to Params when safe search is enabled.
+	SafeParams map[string]string `json:"safe_params,omitempty"`
+	// UnsafeParams are added to Params when safe search is disabled.
+	UnsafeParams map[string]string `json:"unsafe_params,omitempty"`
+	// Headers to set on each request.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Defaults are values used for placeholders when they are not set in the request.
+	Defaults map[string]string `json:"defaults,omitempty"`
+	// Languages supported by the provider. Empty list means any language.
+	Languages []string `json:"languages,omitempty"`
+	// Regions supported by the provider. Empty list means any region.
+	Regions []string `json:"regions,omitempty"`
+
+	// Results is a selector for a container of a single result.
+	Results string `json:"results"`
+	// Title, Link and Snippet are selectors inside the result container.
+	// Link is usually set as {"selector": "a", "attr": "href"}.
+	Title   Field `json:"title"`
+	Link    Field `json:"link"`
+	Snippet Field `json:"snippet"`
+	// Unwrap is a list of query parameters of redirect links that contain the actual URL.
+	Unwrap []string `json:"unwrap,omitempty"`
+
+	// Pagination describes how to request the next page. If not set, only one page is returned.
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes how to request the next page of results.
+// Only one of the fields should be set.
+type Pagination struct {
+	// Offset pagination sets {offset} and {page} placeholders.
+	Offset *OffsetPagination `json:"offset,omitempty"`
+	// Next is a link to the next page.
+	Next *Field `json:"next,omitempty"`
+	// Form is a selector of a form that requests the next page. All inputs with values are submitted.
+	Form string `json:"form,omitempty"`
+}
+
+type OffsetPagination struct {
+	// Start is the value of {offset} on the first page.
+	Start int `json:"start"`
+	// Step is the number of results per page.
+	Step int `json:"step"`
+	// FirstPage is the value of {page} on the first page. Defaults to 1.
+	FirstPage *int `json:"first_page,omitempty"`
+}
+
+// Field is a selector for a text or an attribute value.
+// It can be set either as a selector string, or as an object with "selector" and "attr".
+type Field struct {
+	Selector string `json:"selector"`
+	// Attr is an attribute name. If not set, the text of the element is used.
+	Attr string `json:"attr,omitempty"`
+}
+
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = Field{Selector: s}
+		return nil
+	}
+	type field Field
+	var v field
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = Field(v)
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("provider name must be set")
+	}
+	if _, err := url.Parse(c.URL); err != nil || !strings.HasPrefix(c.URL, "http") {
+		return fmt.Errorf("%s: invalid url: %q", c.Name, c.URL)
+	}
+	switch strings.ToUpper(c.Method) {
+	case "", "GET", "POST":
+	default:
+		return fmt.Errorf("%s: unsupported method: %q", c.Name, c.Method)
+	}
+	if c.Results == "" || c.Link.Selector == "" {
+		return fmt.Errorf("%s: results and link selectors must be set", c.Name)
+	}
+	if p := c.Pagination; p != nil {
+		n := 0
+		if p.Offset != nil {
+			n++
+			if p.Offset.Step <= 0 {
+				return fmt.Errorf("%s: pagination step must be positive", c.Name)
+			}
+		}
+		if p.Next != nil {
+			n++
+		}
+		if p.Form != "" {
+			n++
+		}
+		if n != 1 {
+			return fmt.Errorf("%s: exactly one pagination method must be set", c.Name)
+		}
+	}
+	return nil
+}
+
+// placeholders returns placeholder values for a given page.
+func (c *Config) placeholders(vars map[string]string, page int) map[string]string {
+	out := make(map[string]string, len(vars)+2)
+	for k, v := range c.Defaults {
+		out[k] = v
+	}
+	for k, v := range vars {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	if p := c.Pagination; p != nil && p.Offset != nil {
+		first := 1
+		if p.Offset.FirstPage != nil {
+			first = *p.Offset.FirstPage
+		}
+		out["offset"] = strconv.Itoa(p.Offset.Start + page*p.Offset.Step)
+		out["page"] = strconv.Itoa(first + page)
+	}
+	return out
+}
+
+// expand substitutes placeholders in a parameter value.
+func expand(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	for k, v := range vars {
+		s = strings.Replace(s, "{"+k+"}", v, -1)
+	}
+	return s
+}
+
+// ReadConfig reads a provider config from a JSON file.
+func ReadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %v", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// ReadConfigs reads provider configs from a file or all JSON files in a directory.
+func ReadConfigs(path string) ([]*Config, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		c, err := ReadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		return []*Config{c}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []*Config
+	for _, name := range files {
+		c, err := ReadConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -0,0 +1,459 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName = "scraper"
+
+	// EnvConfigs is a list of scraper config files or directories with them.
+	EnvConfigs = "METAS_SCRAPERS"
+)
+
+func init() {
+	for _, path := range providers.EnvList(EnvConfigs) {
+		list, err := ReadConfigs(path)
+		if err != nil {
+			log.Printf("scraper: %v", err)
+			continue
+		}
+		for _, c := range list {
+			if err := Register(c); err != nil {
+				log.Printf("scraper: %v", err)
+			}
+		}
+	}
+}
+
+var (
+	regMu      sync.Mutex
+	registered = make(map[string]bool)
+)
+
+// Register registers a scraper provider with a given config.
+// Providers are registered under a "scraper:" prefix, thus they never replace built-in providers.
+func Register(c *Config) error {
+	regMu.Lock()
+	defer regMu.Unlock()
+	if registered[c.Name] {
+		return fmt.Errorf("%s: duplicate scraper name", c.Name)
+	}
+	registered[c.Name] = true
+	providers.Register(provName+":"+c.Name, func(ctx context.Context) (providers.Provider, error) {
+		return New(c)
+	})
+	return nil
+}
+
+var _ search.Service = (*Service)(nil)
+
+// New creates a scraper provider from a config.
+func New(c *Config) (*Service, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(""),
+		conf:       c,
+	}, nil
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	conf *Config
+}
+
+func (s *Service) ID() string {
+	return provName + ":" + s.conf.Name
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	if len(s.conf.Languages) == 0 {
+		return nil, nil
+	}
+	names := display.English.Languages()
+	out := make([]search.Language, 0, len(s.conf.Languages))
+	for _, l := range s.conf.Languages {
+		code, err := search.ParseLangCode(l)
+		if err != nil {
+			return nil, fmt.Errorf("%s: cannot parse language %q: %v", s.conf.Name, l, err)
+		}
+		out = append(out, search.Language{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	if len(s.conf.Regions) == 0 {
+		return nil, nil
+	}
+	names := display.English.Regions()
+	out := make([]search.Region, 0, len(s.conf.Regions))
+	for _, r := range s.conf.Regions {
+		code, err := search.ParseRegionCode(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: cannot parse region %q: %v", s.conf.Name, r, err)
+		}
+		out = append(out, search.Region{Code: code, Name: names.Name(code)})
+	}
+	return out, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	vars := map[string]string{
+		"query": req.Query,
+	}
+	if !req.Lang.IsRoot() {
+		base, _ := req.Lang.Base()
+		vars["lang"] = base.String()
+		vars["locale"] = req.Lang.String()
+	}
+	reg := req.Region
+	if reg == (search.RegionCode{}) && !req.Lang.IsRoot() {
+		if r, conf := req.Lang.Region(); conf != language.No {
+			reg = r
+		}
+	}
+	if reg != (search.RegionCode{}) {
+		vars["region"] = reg.String()
+		vars["country"] = strings.ToLower(reg.String())
+	}
+	r := s.request(vars, req.Safe, 0)
+	return &searchIter{s: s, cur: r}
+}
+
+// request builds a request for a given page of results.
+func (s *Service) request(vars map[string]string, safe bool, page int) SearchReq {
+	c := s.conf
+	pv := c.placeholders(vars, page)
+	params := make(url.Values, len(c.Params))
+	add := func(m map[string]string) {
+		for k, v := range m {
+			if v = expand(v, pv); v != "" {
+				params.Set(k, v)
+			}
+		}
+	}
+	add(c.Params)
+	if safe {
+		add(c.SafeParams)
+	} else {
+		add(c.UnsafeParams)
+	}
+	return SearchReq{
+		Method: strings.ToUpper(c.Method),
+		URL:    c.URL,
+		Params: params,
+		Vars:   vars,
+		Safe:   safe,
+		Page:   page,
+	}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, next: resp.Next, fetched: true, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	next    *SearchReq
+	fetched bool
+
+	page []Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || it.next == nil {
+			it.page = nil
+			return false
+		}
+		it.cur, it.next = *it.next, nil
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Results
+	it.next = resp.Next
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return &search.LinkResult{
+		URL: *u, Title: r.Title, Desc: r.Content,
+	}
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+// SearchReq is a single page request. Vars, Safe and Page are used to build requests for next pages.
+type SearchReq struct {
+	Method string            `json:"method,omitempty"`
+	URL    string            `json:"url"`
+	Params url.Values        `json:"params,omitempty"`
+	Vars   map[string]string `json:"vars,omitempty"`
+	Safe   bool              `json:"safe,omitempty"`
+	Page   int               `json:"page,omitempty"`
+}
+
+type Result struct {
+	Title   string
+	URL     string
+	Content string
+}
+
+type SearchResp struct {
+	Results []Result
+	// Next is a request for the next page, if any.
+	Next *SearchReq
+}
+
+// checkRequest makes sure that the request goes to the same site as configured.
+// Requests for next pages come from tokens and result pages, thus they cannot be trusted.
+func (s *Service) checkRequest(r SearchReq) error {
+	if r.Method != "" && r.Method != "GET" && r.Method != "POST" {
+		return fmt.Errorf("%s: unsupported method: %q", s.conf.Name, r.Method)
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return err
+	}
+	base, err := url.Parse(s.conf.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return fmt.Errorf("%s: unexpected request url: %q", s.conf.Name, r.URL)
+	}
+	return nil
+}
+
+func (s *Service) newRequest(r SearchReq) (*http.Request, error) {
+	if err := s.checkRequest(r); err != nil {
+		return nil, err
+	}
+	if r.Method == "POST" {
+		req, err := http.NewRequest("POST", r.URL, strings.NewReader(r.Params.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Params) != 0 {
+		q := u.Query()
+		for k, v := range r.Params {
+			q[k] = v
+		}
+		u.RawQuery = q.Encode()
+	}
+	return http.NewRequest("GET", u.String(), nil)
+}
+
+// SearchRaw fetches a single page of results and extracts them using selectors from the config.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	req, err := s.newRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	doc, err := s.DoHTML(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	base := req.URL
+	c := s.conf
+	out := &SearchResp{}
+	doc.Find(c.Results).Each(func(_ int, sel *goquery.Selection) {
+		link := c.Link.value(sel)
+		if link == "" {
+			return
+		}
+		link = c.unwrap(resolve(base, link))
+		if !strings.HasPrefix(link, "http") {
+			return
+		}
+		out.Results = append(out.Results, Result{
+			Title:   c.Title.value(sel),
+			URL:     link,
+			Content: c.Snippet.value(sel),
+		})
+	})
+	out.Next = s.nextPage(doc, base, r)
+	return out, nil
+}
+
+// nextPage returns a request for the next page of results, or nil if there are no more pages.
+func (s *Service) nextPage(doc *goquery.Document, base *url.URL, r SearchReq) *SearchReq {
+	p := s.conf.Pagination
+	switch {
+	case p == nil:
+		return nil
+	case p.Offset != nil:
+		next := s.request(r.Vars, r.Safe, r.Page+1)
+		return &next
+	case p.Next != nil:
+		link := p.Next.value(doc.Selection)
+		if link == "" {
+			return nil
+		}
+		return &SearchReq{URL: resolve(base, link), Vars: r.Vars, Safe: r.Safe, Page: r.Page + 1}
+	case p.Form != "":
+		form := doc.Find(p.Form).First()
+		if form.Size() == 0 {
+			return nil
+		}
+		params := make(url.Values)
+		form.Find(`input[name]`).Each(func(_ int, sel *goquery.Selection) {
+			if typ, _ := sel.Attr("type"); typ == "submit" || typ == "image" {
+				return
+			}
+			params.Add(sel.AttrOr("name", ""), sel.AttrOr("value", ""))
+		})
+		action := base.String()
+		if a, ok := form.Attr("action"); ok && a != "" {
+			action = resolve(base, a)
+		}
+		method := strings.ToUpper(form.AttrOr("method", "GET"))
+		if method != "POST" {
+			method = ""
+		}
+		return &SearchReq{Method: method, URL: action, Params: params, Vars: r.Vars, Safe: r.Safe, Page: r.Page + 1}
+	}
+	return nil
+}
+
+// value returns a trimmed text or an attribute value of the first element matching the selector.
+// An empty selector matches the element itself.
+func (f Field) value(sel *goquery.Selection) string {
+	if f.Selector != "" {
+		sel = sel.Find(f.Selector)
+	}
+	sel = sel.First()
+	if f.Attr != "" {
+		return strings.TrimSpace(sel.AttrOr(f.Attr, ""))
+	}
+	return strings.TrimSpace(sel.Text())
+}
+
+// resolve converts a possibly relative link to an absolute URL.
+func resolve(base *url.URL, link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(u).String()
+}
+
+// unwrap extracts the target URL from a redirect link using Unwrap parameters.
+func (c *Config) unwrap(link string) string {
+	if len(c.Unwrap) == 0 {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	q := u.Query()
+	for _, name := range c.Unwrap {
+		if v := q.Get(name); strings.HasPrefix(v, "http") {
+			return v
+		}
+	}
+	return link
+}
@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func collect(t *testing.T, it search.ResultIterator) []search.Result {
+	ctx := context.Background()
+	defer it.Close()
+	var out []search.Result
+	for it.Next(ctx) {
+		out = append(out, it.Result())
+	}
+	require.NoError(t, it.Err())
+	return out
+}
+
+func TestFormPagination(t *testing.T) {
+	c, err := ReadConfig("testdata/ddg.json")
+	require.NoError(t, err)
+
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/page1.html", "text/html")
+	page2 := providertest.ServeFile(t, "testdata/page2.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "html.duckduckgo.com", r.Host)
+		require.Equal(t, "/html/", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		reqs = append(reqs, r.PostForm)
+		if r.PostForm.Get("s") == "" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+
+	s, err := New(c)
+	require.NoError(t, err)
+	s.SetHTTPClient(cli)
+	require.Equal(t, "scraper:ddg-html", s.ID())
+
+	got := collect(t, s.Search(context.Background(), search.Request{
+		Query: "solar",
+		Lang:  search.MustParseLangCode("de"),
+		Safe:  true,
+	}))
+	require.Equal(t, []url.Values{
+		{"q": {"solar"}, "kl": {"de-de"}, "kp": {"1"}},
+		{"q": {"solar"}, "s": {"30"}, "dc": {"31"}, "kl": {"us-en"}},
+	}, reqs)
+	require.Len(t, got, 3)
+	require.Equal(t, &search.LinkResult{
+		URL:   *providertest.MustURL("https://en.wikipedia.org/wiki/Solar_System"),
+		Title: "Solar System - Wikipedia",
+		Desc:  "The Solar System is the gravitationally bound system of the Sun.",
+	}, got[0])
+	require.Equal(t, "https://solarsystem.nasa.gov/", got[1].GetURL().String())
+	require.Equal(t, "https://www.britannica.com/science/solar-system", got[2].GetURL().String())
+}
+
+func TestOffsetPagination(t *testing.T) {
+	c := &Config{
+		Name:    "example",
+		URL:     "https://search.example.com/find?src=metas",
+		Params:  map[string]string{"q": "{query}", "first": "{offset}", "hl": "{locale}"},
+		Results: "div.result",
+		Title:   Field{Selector: "a.result__a"},
+		Link:    Field{Selector: "a.result__a", Attr: "href"},
+		Snippet: Field{Selector: ".result__snippet"},
+		Unwrap:  []string{"uddg"},
+		Pagination: &Pagination{
+			Offset: &OffsetPagination{Start: 1, Step: 10},
+		},
+	}
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/page1.html", "text/html")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "GET", r.Method)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("first") == "1" {
+			page1(w, r)
+		} else {
+			w.Write([]byte(`<html><body>No results</body></html>`))
+		}
+	}))
+	s, err := New(c)
+	require.NoError(t, err)
+	s.SetHTTPClient(cli)
+
+	ctx := context.Background()
+	it := s.Search(ctx, search.Request{Query: "solar", Lang: search.MustParseLangCode("en-GB")})
+	require.True(t, it.Next(ctx))
+	tok := it.Token()
+	require.NoError(t, it.Close())
+
+	got := collect(t, s.ContinueSearch(ctx, tok))
+	require.Len(t, got, 1)
+	require.Equal(t, "https://solarsystem.nasa.gov/", got[0].GetURL().String())
+	require.Equal(t, []url.Values{
+		{"src": {"metas"}, "q": {"solar"}, "first": {"1"}, "hl": {"en-GB"}},
+		{"src": {"metas"}, "q": {"solar"}, "first": {"1"}, "hl": {"en-GB"}},
+		{"src": {"metas"}, "q": {"solar"}, "first": {"11"}, "hl": {"en-GB"}},
+	}, reqs)
+}
+
+func TestForeignToken(t *testing.T) {
+	c, err := ReadConfig("testdata/ddg.json")
+	require.NoError(t, err)
+	c.Headers = map[string]string{"Cookie": "secret"}
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s%s", r.Method, r.Host, r.URL)
+	}))
+	s, err := New(c)
+	require.NoError(t, err)
+	s.SetHTTPClient(cli)
+
+	ctx := context.Background()
+	for _, tok := range []string{
+		`{"req":{"url":"http://attacker.example/steal"}}`,
+		`{"req":{"url":"http://html.duckduckgo.com/html/"}}`,
+		`{"req":{"method":"DELETE","url":"https://html.duckduckgo.com/html/"}}`,
+	} {
+		it := s.ContinueSearch(ctx, search.Token(tok))
+		require.False(t, it.Next(ctx), tok)
+		require.Error(t, it.Err(), tok)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	c := &Config{Name: "test-register"}
+	require.NoError(t, Register(c))
+	require.Error(t, Register(c))
+}
+
+func TestValidate(t *testing.T) {
+	c := &Config{
+		Name:    "bad",
+		URL:     "https://example.com/search",
+		Results: "div.result",
+		Link:    Field{Selector: "a", Attr: "href"},
+		Pagination: &Pagination{
+			Offset: &OffsetPagination{Step: 10},
+			Form:   "form",
+		},
+	}
+	require.Error(t, c.Validate())
+	c.Pagination.Form = ""
+	require.NoError(t, c.Validate())
+	c.URL = "example.com"
+	require.Error(t, c.Validate())
+}
@@ -0,0 +1,392 @@
+package searx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName   = "searx"
+	searchPath = "/search"
+
+	// EnvInstances is a list of SearX instance URLs to use.
+	EnvInstances = "METAS_SEARX_INSTANCES"
+	// EnvCategories is a list of SearX categories to search in.
+	EnvCategories = "METAS_SEARX_CATEGORIES"
+)
+
+const (
+	categoryGeneral = "general"
+	categoryImages  = "images"
+	categoryVideos  = "videos"
+)
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		list := providers.EnvList(EnvInstances)
+		if len(list) == 0 {
+			return nil, nil
+		}
+		s := New(list...)
+		if cats := providers.EnvList(EnvCategories); len(cats) != 0 {
+			s.Categories = cats
+		}
+		return s, nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+// New creates a provider for a given list of SearX or SearXNG instances.
+// Instances are tried in order; an instance that fails is skipped until other instances fail as well.
+func New(instances ...string) *Service {
+	list := make([]string, 0, len(instances))
+	for _, v := range instances {
+		list = append(list, strings.TrimSuffix(v, "/"))
+	}
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(""),
+		Instances:  list,
+		Categories: []string{categoryGeneral},
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	Instances  []string
+	Categories []string
+
+	mu  sync.Mutex
+	cur int // index of the last instance that responded
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil // instances accept any language and pass it to the engines
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil // regions are passed as a part of the language code
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query:      req.Query,
+		Page:       1,
+		Categories: s.Categories,
+		SafeSearch: req.Safe,
+	}
+	if !req.Lang.IsRoot() {
+		r.Language = req.Lang.String()
+		if req.Region != (search.RegionCode{}) {
+			base, _ := req.Lang.Base()
+			r.Language = base.String() + "-" + req.Region.String()
+		}
+	}
+	return &searchIter{s: s, cur: r}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	page, err := resp.results(t.Cur.Page == 1)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, fetched: true, page: page, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	fetched bool
+
+	page []search.Result
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 {
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page, it.err = resp.results(it.cur.Page == 1)
+	it.i = -1
+	return it.err == nil && len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	return it.page[it.i]
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query      string   `json:"q"`
+	Page       int      `json:"page"`
+	Language   string   `json:"lang"`
+	Categories []string `json:"cats"`
+	SafeSearch bool     `json:"safe"`
+}
+
+type Result struct {
+	URL       string   `json:"url"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Engines   []string `json:"engines"`
+	Category  string   `json:"category"`
+	ImageURL  string   `json:"img_src"`
+	Thumbnail string   `json:"thumbnail_src"`
+}
+
+type Infobox struct {
+	Title      string `json:"infobox"`
+	ID         string `json:"id"`
+	Content    string `json:"content"`
+	ImageURL   string `json:"img_src"`
+	URLs       []Link `json:"urls"`
+	Attributes []struct {
+		Label string `json:"label"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type SearchResp struct {
+	Query       string    `json:"query"`
+	Results     []Result  `json:"results"`
+	Infoboxes   []Infobox `json:"infoboxes"`
+	Suggestions []string  `json:"suggestions"`
+}
+
+func parseImage(s string) (*search.Image, error) {
+	if s == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &search.Image{URL: *u}, nil
+}
+
+func (r *Result) toResult() (search.Result, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	link := search.LinkResult{URL: *u, Title: r.Title, Desc: r.Content}
+	switch r.Category {
+	case categoryImages:
+		img, err := parseImage(r.ImageURL)
+		if err != nil {
+			return nil, err
+		} else if img == nil {
+			break
+		}
+		thumb, err := parseImage(r.Thumbnail)
+		if err != nil {
+			return nil, err
+		}
+		return &search.ImageResult{
+			Image: *img, Title: r.Title, Desc: r.Content,
+			PageURL: u, Thumbnail: thumb,
+		}, nil
+	case categoryVideos:
+		thumb, err := parseImage(r.Thumbnail)
+		if err != nil {
+			return nil, err
+		}
+		return &search.VideoResult{LinkResult: link, Thumbnail: thumb}, nil
+	}
+	return &link, nil
+}
+
+func (b *Infobox) toResult() (*search.EntityResult, error) {
+	addr := b.ID
+	if !strings.HasPrefix(addr, "http") {
+		addr = ""
+		if len(b.URLs) != 0 {
+			addr = b.URLs[0].URL
+		}
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	img, err := parseImage(b.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+	r := &search.EntityResult{
+		LinkResult: search.LinkResult{URL: *u, Title: b.Title, Desc: b.Content},
+		Image:      img,
+	}
+	for _, a := range b.Attributes {
+		if r.Attributes == nil {
+			r.Attributes = make(map[string]string)
+		}
+		r.Attributes[a.Label] = a.Value
+	}
+	return r, nil
+}
+
+// results converts a response to a list of results. Infoboxes are placed first, if requested.
+func (resp *SearchResp) results(infoboxes bool) ([]search.Result, error) {
+	var out []search.Result
+	if infoboxes {
+		for _, b := range resp.Infoboxes {
+			r, err := b.toResult()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+	}
+	for _, v := range resp.Results {
+		r, err := v.toResult()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *Service) searchInstance(ctx context.Context, inst string, r SearchReq) (*SearchResp, error) {
+	params := make(url.Values)
+	params.Set("q", r.Query)
+	params.Set("format", "json")
+	params.Set("pageno", strconv.Itoa(r.Page))
+	if len(r.Categories) != 0 {
+		params.Set("categories", strings.Join(r.Categories, ","))
+	}
+	if r.Language != "" {
+		params.Set("language", r.Language)
+	} else {
+		params.Set("language", "all")
+	}
+	if r.SafeSearch {
+		params.Set("safesearch", "2")
+	} else {
+		params.Set("safesearch", "0")
+	}
+	var out SearchResp
+	if err := s.GetJSON(ctx, inst+searchPath, params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchRaw runs the search on one of the instances. If the instance fails, the next one is tried.
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	if len(s.Instances) == 0 {
+		return nil, fmt.Errorf("no searx instances configured")
+	}
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	s.mu.Lock()
+	start := s.cur
+	s.mu.Unlock()
+
+	var last error
+	for i := 0; i < len(s.Instances); i++ {
+		j := (start + i) % len(s.Instances)
+		resp, err := s.searchInstance(ctx, s.Instances[j], r)
+		if err == nil {
+			s.mu.Lock()
+			s.cur = j
+			s.mu.Unlock()
+			return resp, nil
+		}
+		last = fmt.Errorf("%s: %v", s.Instances[j], err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, last
+}
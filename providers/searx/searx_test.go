@@ -0,0 +1,108 @@
+package searx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	broken := 0
+	srv1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broken++
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv1.Close()
+
+	var reqs []url.Values
+	page := providertest.ServeFile(t, "testdata/solar.json", "application/json")
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("pageno") != "1" {
+			w.Write([]byte(`{"query":"solar","results":[],"infoboxes":[]}`))
+			return
+		}
+		page(w, r)
+	}))
+	defer srv2.Close()
+
+	s := New(srv1.URL, srv2.URL+"/")
+	s.Categories = []string{"general", "images", "videos"}
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{
+		Query:  "solar",
+		Lang:   search.MustParseLangCode("de"),
+		Region: search.MustParseRegionCode("AT"),
+		Safe:   true,
+	})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []search.Result{
+		&search.EntityResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://en.wikipedia.org/wiki/Solar_energy"),
+				Title: "Solar energy",
+				Desc:  "Solar energy is radiant light and heat from the Sun that is harnessed using a range of technologies.",
+			},
+			Image:      &search.Image{URL: *providertest.MustURL("https://upload.wikimedia.org/wikipedia/commons/solar.jpg")},
+			Attributes: map[string]string{"Inception": "1954"},
+		},
+		&search.LinkResult{
+			URL:   *providertest.MustURL("https://en.wikipedia.org/wiki/Solar_energy"),
+			Title: "Solar energy - Wikipedia",
+			Desc:  "Solar energy is radiant light and heat from the Sun.",
+		},
+		&search.ImageResult{
+			Image:     search.Image{URL: *providertest.MustURL("https://live.staticflickr.com/1/solar.jpg")},
+			Title:     "Solar panel",
+			Desc:      "Solar panels on the roof",
+			PageURL:   providertest.MustURL("https://www.flickr.com/photos/nasa/1"),
+			Thumbnail: &search.Image{URL: *providertest.MustURL("https://live.staticflickr.com/1/solar_n.jpg")},
+		},
+		&search.VideoResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://www.youtube.com/watch?v=abc"),
+				Title: "How solar panels work",
+			},
+			Thumbnail: &search.Image{URL: *providertest.MustURL("https://i.ytimg.com/vi/abc/hqdefault.jpg")},
+		},
+	}, got)
+
+	// the broken instance is only tried once
+	require.Equal(t, 1, broken)
+	require.Len(t, reqs, 2)
+	q := reqs[0]
+	require.Equal(t, "solar", q.Get("q"))
+	require.Equal(t, "json", q.Get("format"))
+	require.Equal(t, "de-AT", q.Get("language"))
+	require.Equal(t, "2", q.Get("safesearch"))
+	require.Equal(t, "general,images,videos", q.Get("categories"))
+	require.Equal(t, "2", reqs[1].Get("pageno"))
+}
+
+func TestSearchFail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	s := New(srv.URL, srv.URL)
+	ctx := context.Background()
+	it := s.Search(ctx, search.Request{Query: "solar"})
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.Error(t, it.Err())
+}
@@ -0,0 +1,351 @@
+package stackexchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName    = "stackexchange"
+	baseURL     = "https://api.stackexchange.com/2.3"
+	searchPath  = "/search/advanced"
+	similarPath = "/similar"
+	defaultSite = "stackoverflow"
+
+	// EnvSites is a list of Stack Exchange sites to create providers for, e.g. "stackoverflow superuser".
+	EnvSites = "METAS_STACKEXCHANGE_SITES"
+	// EnvKey is an optional API key that increases the request quota.
+	EnvKey = "METAS_STACKEXCHANGE_KEY"
+)
+
+var (
+	DefaultPageSize = 30
+	maxDescLen      = 300
+)
+
+func init() {
+	sites := providers.EnvList(EnvSites)
+	if len(sites) == 0 {
+		sites = []string{defaultSite}
+	}
+	for _, site := range sites {
+		site := site
+		providers.Register(provName+"-"+site, func(ctx context.Context) (providers.Provider, error) {
+			s := New(site)
+			s.Key = os.Getenv(EnvKey)
+			return s, nil
+		})
+	}
+}
+
+var (
+	_ search.Service       = (*Service)(nil)
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// New creates a provider for a given Stack Exchange site, e.g. "stackoverflow" or "superuser".
+func New(site string) *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(baseURL),
+		Site:       site,
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+
+	Site string
+	Key  string
+
+	mu        sync.Mutex
+	notBefore time.Time // set from the backoff field of the last response
+}
+
+func (s *Service) ID() string {
+	return provName + "-" + s.Site
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &searchIter{s: s, cur: SearchReq{Query: req.Query, Page: 1}}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, more: resp.HasMore, fetched: true, page: resp.Items, i: t.Off}
+}
+
+type searchIter struct {
+	s       *Service
+	cur     SearchReq
+	more    bool
+	fetched bool
+
+	page []Question
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.fetched {
+		if len(it.page) == 0 || !it.more {
+			it.page = nil
+			return false
+		}
+		it.cur.Page++
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.fetched = true
+	it.page = resp.Items
+	it.more = resp.HasMore
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i < 0 || it.i >= len(it.page) {
+		return nil
+	}
+	r, err := it.page[it.i].toResult()
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return r
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+type SearchReq struct {
+	Query string `json:"q"`
+	Page  int    `json:"page"`
+}
+
+type Question struct {
+	ID               int      `json:"question_id"`
+	Link             string   `json:"link"`
+	Title            string   `json:"title"`
+	Body             string   `json:"body"`
+	Tags             []string `json:"tags"`
+	Score            int      `json:"score"`
+	AnswerCount      int      `json:"answer_count"`
+	IsAnswered       bool     `json:"is_answered"`
+	AcceptedAnswerID int      `json:"accepted_answer_id"`
+	LastActivity     int64    `json:"last_activity_date"`
+}
+
+// excerpt converts an HTML body to text and returns its first paragraph, truncated to a given length.
+func excerpt(body string, n int) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	sel := doc.Find("p").First()
+	if sel.Size() == 0 {
+		sel = doc.Selection
+	}
+	s := strings.Join(strings.Fields(sel.Text()), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
+
+func (q *Question) toResult() (*search.QAResult, error) {
+	u, err := url.Parse(q.Link)
+	if err != nil {
+		return nil, err
+	}
+	r := &search.QAResult{
+		LinkResult: search.LinkResult{
+			URL:   *u,
+			Title: html.UnescapeString(q.Title),
+			Desc:  excerpt(q.Body, maxDescLen),
+		},
+		Score:    q.Score,
+		Answers:  q.AnswerCount,
+		Accepted: q.AcceptedAnswerID != 0,
+		Tags:     q.Tags,
+	}
+	if q.LastActivity != 0 {
+		r.Updated = time.Unix(q.LastActivity, 0).UTC()
+	}
+	return r, nil
+}
+
+type SearchResp struct {
+	Items          []Question `json:"items"`
+	HasMore        bool       `json:"has_more"`
+	QuotaRemaining int        `json:"quota_remaining"`
+	// Backoff is the number of seconds to wait before sending the next request.
+	Backoff int `json:"backoff"`
+}
+
+func (s *Service) params() url.Values {
+	params := make(url.Values)
+	params.Set("site", s.Site)
+	params.Set("order", "desc")
+	params.Set("sort", "relevance")
+	if s.Key != "" {
+		params.Set("key", s.Key)
+	}
+	return params
+}
+
+// wait blocks until the API allows the next request. The API throttles clients that ignore the backoff.
+func (s *Service) wait(ctx context.Context) error {
+	s.mu.Lock()
+	d := time.Until(s.notBefore)
+	s.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	if dl, ok := ctx.Deadline(); ok && time.Until(dl) < d {
+		return fmt.Errorf("%s: backoff for %v", s.Site, d.Round(time.Second))
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff delays the next request by a given number of seconds.
+func (s *Service) backoff(sec int) {
+	if sec <= 0 {
+		return
+	}
+	t := time.Now().Add(time.Duration(sec) * time.Second)
+	s.mu.Lock()
+	if t.After(s.notBefore) {
+		s.notBefore = t
+	}
+	s.mu.Unlock()
+}
+
+// get sends an API request, respecting the backoff requested by the API.
+func (s *Service) get(ctx context.Context, path string, params url.Values) (*SearchResp, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	var out SearchResp
+	if err := s.GetJSON(ctx, path, params, &out); err != nil {
+		return nil, fmt.Errorf("%s: %v", s.Site, err)
+	}
+	s.backoff(out.Backoff)
+	return &out, nil
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	params := s.params()
+	params.Set("q", r.Query)
+	params.Set("page", strconv.Itoa(r.Page))
+	params.Set("pagesize", strconv.Itoa(DefaultPageSize))
+	params.Set("filter", "withbody")
+	return s.get(ctx, searchPath, params)
+}
+
+// AutoComplete returns titles of questions similar to a given text.
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	params := s.params()
+	params.Set("title", req.Text)
+	params.Set("pagesize", "10")
+	resp, err := s.get(ctx, similarPath, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(resp.Items))
+	for _, q := range resp.Items {
+		out = append(out, html.UnescapeString(q.Title))
+	}
+	return out, nil
+}
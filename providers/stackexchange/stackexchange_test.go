@@ -0,0 +1,109 @@
+package stackexchange
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	var reqs []url.Values
+	page1 := providertest.ServeFile(t, "testdata/search_1.json", "application/json")
+	page2 := providertest.ServeFile(t, "testdata/search_2.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "api.stackexchange.com", r.Host)
+		require.Equal(t, "/2.3"+searchPath, r.URL.Path)
+		q := r.URL.Query()
+		reqs = append(reqs, q)
+		if q.Get("page") == "1" {
+			page1(w, r)
+		} else {
+			page2(w, r)
+		}
+	}))
+	s := New("superuser")
+	s.SetHTTPClient(cli)
+	require.Equal(t, "stackexchange-superuser", s.ID())
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "buffered channel full"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, reqs, 2)
+	require.Equal(t, "superuser", reqs[0].Get("site"))
+	require.Equal(t, "buffered channel full", reqs[0].Get("q"))
+	require.Equal(t, "2", reqs[1].Get("page"))
+
+	require.Len(t, got, 3)
+	require.Equal(t, &search.QAResult{
+		LinkResult: search.LinkResult{
+			URL:   *providertest.MustURL("https://stackoverflow.com/questions/25657207/how-to-know-a-buffered-channel-is-full"),
+			Title: "How to know a buffered channel is full",
+			Desc:  "How to know a buffered channel is full? I don't want to be blocked.",
+		},
+		Score:    57,
+		Answers:  4,
+		Accepted: true,
+		Tags:     []string{"go", "goroutine", "channel"},
+		Updated:  time.Unix(1700000000, 0).UTC(),
+	}, got[0])
+	q := got[1].(*search.QAResult)
+	require.Equal(t, `Select on "closed" channel returns zero & never blocks`, q.Title)
+	require.False(t, q.Accepted)
+	require.Equal(t, -1, q.Score)
+}
+
+func TestAutoComplete(t *testing.T) {
+	similar := providertest.ServeFile(t, "testdata/similar.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/2.3"+similarPath, r.URL.Path)
+		require.Equal(t, "channel full", r.URL.Query().Get("title"))
+		require.Equal(t, "stackoverflow", r.URL.Query().Get("site"))
+		similar(w, r)
+	}))
+	s := New("stackoverflow")
+	s.SetHTTPClient(cli)
+
+	got, err := s.AutoComplete(context.Background(), autocomplete.Request{Text: "channel full"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"How to know a buffered channel is full",
+		"Checking if a channel has a ready-to-read value, using Go",
+		"How to check a channel is closed or not without reading it?",
+	}, got)
+}
+
+func TestBackoff(t *testing.T) {
+	n := 0
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [], "has_more": false, "backoff": 10}`))
+	}))
+	s := New("stackoverflow")
+	s.SetHTTPClient(cli)
+
+	_, err := s.SearchRaw(context.Background(), SearchReq{Query: "go"})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// the next request must wait, thus it fails if the deadline is closer
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = s.SearchRaw(ctx, SearchReq{Query: "go"})
+	require.Error(t, err)
+	_, err = s.AutoComplete(ctx, autocomplete.Request{Text: "go"})
+	require.Error(t, err)
+	require.Equal(t, 1, n)
+}
@@ -0,0 +1,520 @@
+package wikidata
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dennwc/metasearch/providers"
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	provName        = "wikidata"
+	defaultLanguage = "en"
+	apiURL          = "https://www.wikidata.org/w/api.php"
+	entityURL       = "https://www.wikidata.org/wiki/"
+	commonsFileURL  = "https://commons.wikimedia.org/wiki/Special:FilePath/"
+)
+
+const (
+	propInstanceOf = "P31"
+	propImage      = "P18"
+)
+
+var (
+	DefaultLimit         = 10
+	DefaultThumbnailSize = 300
+)
+
+// attributes maps Wikidata properties to entity attributes.
+var attributes = map[string]string{
+	"P569":  search.AttrBirthDate,
+	"P570":  search.AttrDeathDate,
+	"P571":  search.AttrInception,
+	"P1082": search.AttrPopulation,
+	"P856":  search.AttrWebsite,
+}
+
+// categories maps common classes to a broad category of the entity.
+var categories = map[string]string{
+	"Q5":        "person",
+	"Q515":      "place", // city
+	"Q1549591":  "place", // big city
+	"Q6256":     "place", // country
+	"Q3624078":  "place", // sovereign state
+	"Q486972":   "place", // human settlement
+	"Q35657":    "place", // state of the United States
+	"Q8502":     "place", // mountain
+	"Q4022":     "place", // river
+	"Q43229":    "organization",
+	"Q4830453":  "organization",  // business
+	"Q891723":   "organization",  // public company
+	"Q3918":     "organization",  // university
+	"Q11424":    "creative work", // film
+	"Q5398426":  "creative work", // television series
+	"Q7725634":  "creative work", // literary work
+	"Q482994":   "creative work", // album
+	"Q7366":     "creative work", // song
+	"Q7889":     "creative work", // video game
+	"Q7397":     "software",
+	"Q341":      "software", // free software
+	"Q9143":     "software", // programming language
+	"Q16521":    "taxon",
+	"Q11344":    "chemical element",
+	"Q11173":    "chemical compound",
+	"Q1656682":  "event",
+	"Q198":      "event", // war
+	"Q13406463": "list",
+}
+
+func init() {
+	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
+		return New(), nil
+	})
+}
+
+var _ search.Service = (*Service)(nil)
+
+func New() *Service {
+	return &Service{
+		HTTPClient: providers.NewHTTPClient(""),
+	}
+}
+
+type Service struct {
+	providers.HTTPClient
+}
+
+func (*Service) ID() string {
+	return provName
+}
+
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	return nil, nil // labels are multilingual, any language is accepted
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil // not regional
+}
+
+func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	r := SearchReq{
+		Query: req.Query,
+		Limit: DefaultLimit,
+	}
+	if !req.Lang.IsRoot() {
+		base, _ := req.Lang.Base()
+		r.Language = base.String()
+	}
+	return &searchIter{s: s, cur: r, next: -1}
+}
+
+func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	var t token
+	if err := json.Unmarshal([]byte(tok), &t); err != nil {
+		return &searchIter{err: err}
+	}
+	resp, err := s.SearchRaw(ctx, t.Cur)
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{s: s, cur: t.Cur, next: resp.Continue, page: resp.Results, i: t.Off}
+}
+
+type searchIter struct {
+	s    *Service
+	cur  SearchReq
+	next int // offset of the next page; 0 if there are no more pages, -1 if nothing was fetched yet
+
+	page []Entity
+	i    int
+	err  error
+}
+
+func (it *searchIter) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.next == 0 {
+		it.page = nil
+		return false
+	} else if it.next > 0 {
+		it.cur.Offset = it.next
+	}
+	it.page = nil
+	resp, err := it.s.SearchRaw(ctx, it.cur)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.next = resp.Continue
+	it.page = resp.Results
+	it.i = -1
+	return len(it.page) > 0
+}
+
+func (it *searchIter) Buffered() int {
+	n := len(it.page) - (it.i + 1)
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+func (it *searchIter) Next(ctx context.Context) bool {
+	if it.err != nil || it.s == nil {
+		return false
+	}
+	if it.i+1 >= len(it.page) {
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+	it.i++
+	return true
+}
+
+func (it *searchIter) Close() error {
+	it.page = nil
+	return nil
+}
+
+func (it *searchIter) Err() error {
+	return it.err
+}
+
+func (it *searchIter) Result() search.Result {
+	if it.i >= len(it.page) {
+		return nil
+	}
+	r := it.page[it.i]
+	u, err := url.Parse(entityURL + r.ID)
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	res := &search.EntityResult{
+		LinkResult: search.LinkResult{
+			URL: *u, Title: r.Label, Desc: r.Desc,
+		},
+		Type:       r.Type,
+		Category:   r.Category,
+		Attributes: r.Attributes,
+	}
+	if r.Image != "" {
+		u, err := url.Parse(imageURL(r.Image, DefaultThumbnailSize))
+		if err != nil {
+			it.err = err
+			return nil
+		}
+		res.Image = &search.Image{URL: *u, Width: DefaultThumbnailSize}
+	}
+	return res
+}
+
+func (it *searchIter) Token() search.Token {
+	data, err := json.Marshal(token{
+		Cur: it.cur,
+		Off: it.i,
+	})
+	if err != nil {
+		it.err = err
+		return nil
+	}
+	return search.Token(data)
+}
+
+type token struct {
+	Cur SearchReq `json:"req"`
+	Off int       `json:"off,omitempty"`
+}
+
+// imageURL returns a URL of a thumbnail for a file on Wikimedia Commons.
+func imageURL(file string, width int) string {
+	file = strings.Replace(file, " ", "_", -1)
+	return commonsFileURL + url.PathEscape(file) + "?width=" + strconv.Itoa(width)
+}
+
+type SearchReq struct {
+	Query    string `json:"q"`
+	Language string `json:"lang"`
+	Offset   int    `json:"off"`
+	Limit    int    `json:"limit"`
+}
+
+type SearchResp struct {
+	Results  []Entity
+	Continue int // offset of the next page; 0 if there are no more results
+}
+
+// Entity is a Wikidata item with resolved claims.
+type Entity struct {
+	ID         string
+	Label      string
+	Desc       string
+	Type       string // label of the first "instance of" class
+	Category   string
+	Image      string // file name on Wikimedia Commons
+	Attributes map[string]string
+}
+
+type searchResult struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Desc  string `json:"description"`
+}
+
+type searchResponse struct {
+	Search   []searchResult `json:"search"`
+	Continue int            `json:"search-continue"`
+}
+
+type entity struct {
+	ID           string               `json:"id"`
+	Labels       map[string]langValue `json:"labels"`
+	Descriptions map[string]langValue `json:"descriptions"`
+	Claims       map[string][]claim   `json:"claims"`
+}
+
+type langValue struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+type claim struct {
+	Rank     string `json:"rank"`
+	MainSnak struct {
+		SnakType  string `json:"snaktype"`
+		DataValue struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"datavalue"`
+	} `json:"mainsnak"`
+}
+
+type entitiesResponse struct {
+	Entities map[string]entity `json:"entities"`
+}
+
+// label returns a label of the entity in a given language, falling back to English.
+func (e *entity) label(lang string) string {
+	if v, ok := e.Labels[lang]; ok {
+		return v.Value
+	}
+	return e.Labels[defaultLanguage].Value
+}
+
+func (e *entity) desc(lang string) string {
+	if v, ok := e.Descriptions[lang]; ok {
+		return v.Value
+	}
+	return e.Descriptions[defaultLanguage].Value
+}
+
+// values returns claims of a given property, preferred ranks first. Deprecated claims are skipped.
+func (e *entity) values(prop string) []claim {
+	var pref, norm []claim
+	for _, c := range e.Claims[prop] {
+		if c.MainSnak.SnakType != "value" {
+			continue
+		}
+		switch c.Rank {
+		case "preferred":
+			pref = append(pref, c)
+		case "deprecated":
+		default:
+			norm = append(norm, c)
+		}
+	}
+	return append(pref, norm...)
+}
+
+// entityIDs returns item IDs referenced by claims of a given property.
+func (e *entity) entityIDs(prop string) []string {
+	var out []string
+	for _, c := range e.values(prop) {
+		var v struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(c.MainSnak.DataValue.Value, &v); err == nil && v.ID != "" {
+			out = append(out, v.ID)
+		}
+	}
+	return out
+}
+
+// format converts a claim value to a human-readable form.
+func (c *claim) format() (string, bool) {
+	dv := c.MainSnak.DataValue
+	switch dv.Type {
+	case "string":
+		var v string
+		if err := json.Unmarshal(dv.Value, &v); err != nil {
+			return "", false
+		}
+		return v, v != ""
+	case "time":
+		var v struct {
+			Time      string `json:"time"`
+			Precision int    `json:"precision"`
+		}
+		if err := json.Unmarshal(dv.Value, &v); err != nil {
+			return "", false
+		}
+		return formatTime(v.Time, v.Precision)
+	case "quantity":
+		var v struct {
+			Amount string `json:"amount"`
+		}
+		if err := json.Unmarshal(dv.Value, &v); err != nil {
+			return "", false
+		}
+		return strings.TrimPrefix(v.Amount, "+"), v.Amount != ""
+	}
+	return "", false
+}
+
+// formatTime formats Wikibase time value (+1952-03-11T00:00:00Z) according to its precision.
+func formatTime(s string, prec int) (string, bool) {
+	bc := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	i := strings.Index(s, "T")
+	if i < 0 {
+		return "", false
+	}
+	s = s[:i]
+	parts := strings.SplitN(s, "-", 3)
+	if len(parts) != 3 {
+		return "", false
+	}
+	parts[0] = strings.TrimLeft(parts[0], "0")
+	switch {
+	case prec >= 11: // day
+	case prec == 10: // month
+		parts = parts[:2]
+	default:
+		parts = parts[:1]
+	}
+	s = strings.Join(parts, "-")
+	if bc {
+		s += " BC"
+	}
+	return s, true
+}
+
+func (s *Service) getEntities(ctx context.Context, ids []string, props, lang string) (map[string]entity, error) {
+	params := make(url.Values)
+	params.Set("action", "wbgetentities")
+	params.Set("ids", strings.Join(ids, "|"))
+	params.Set("props", props)
+	params.Set("languages", lang+"|"+defaultLanguage)
+	params.Set("format", "json")
+
+	var resp entitiesResponse
+	if err := s.GetJSON(ctx, apiURL, params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entities, nil
+}
+
+func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
+	if r.Language == "" {
+		r.Language = defaultLanguage
+	}
+	if r.Limit <= 0 {
+		r.Limit = DefaultLimit
+	}
+	params := make(url.Values)
+	params.Set("action", "wbsearchentities")
+	params.Set("search", r.Query)
+	params.Set("language", r.Language)
+	params.Set("uselang", r.Language)
+	params.Set("type", "item")
+	params.Set("limit", strconv.Itoa(r.Limit))
+	params.Set("continue", strconv.Itoa(r.Offset))
+	params.Set("format", "json")
+
+	var sresp searchResponse
+	if err := s.GetJSON(ctx, apiURL, params, &sresp); err != nil {
+		return nil, err
+	}
+	out := &SearchResp{Continue: sresp.Continue}
+	if len(sresp.Search) == 0 {
+		return out, nil
+	}
+	ids := make([]string, 0, len(sresp.Search))
+	for _, e := range sresp.Search {
+		ids = append(ids, e.ID)
+	}
+	entities, err := s.getEntities(ctx, ids, "labels|descriptions|claims", r.Language)
+	if err != nil {
+		return nil, err
+	}
+	// collect classes to resolve their labels
+	var classes []string
+	seen := make(map[string]struct{})
+	for _, e := range entities {
+		ids := e.entityIDs(propInstanceOf)
+		if len(ids) == 0 {
+			continue
+		}
+		if _, ok := seen[ids[0]]; !ok {
+			seen[ids[0]] = struct{}{}
+			classes = append(classes, ids[0])
+		}
+	}
+	var classLabels map[string]entity
+	if len(classes) != 0 {
+		classLabels, err = s.getEntities(ctx, classes, "labels", r.Language)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, sr := range sresp.Search {
+		res := Entity{ID: sr.ID, Label: sr.Label, Desc: sr.Desc}
+		e, ok := entities[sr.ID]
+		if !ok {
+			out.Results = append(out.Results, res)
+			continue
+		}
+		if l := e.label(r.Language); l != "" {
+			res.Label = l
+		}
+		if d := e.desc(r.Language); d != "" {
+			res.Desc = d
+		}
+		for _, id := range e.entityIDs(propInstanceOf) {
+			if res.Type == "" {
+				if c, ok := classLabels[id]; ok {
+					res.Type = c.label(r.Language)
+				}
+			}
+			if cat, ok := categories[id]; ok {
+				res.Category = cat
+				break
+			}
+		}
+		for _, c := range e.values(propImage) {
+			if v, ok := c.format(); ok {
+				res.Image = v
+				break
+			}
+		}
+		for prop, name := range attributes {
+			for _, c := range e.values(prop) {
+				if v, ok := c.format(); ok {
+					if res.Attributes == nil {
+						res.Attributes = make(map[string]string)
+					}
+					res.Attributes[name] = v
+					break
+				}
+			}
+		}
+		out.Results = append(out.Results, res)
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package wikidata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTime(t *testing.T) {
+	for _, c := range []struct {
+		time string
+		prec int
+		exp  string
+	}{
+		{time: "+1952-03-11T00:00:00Z", prec: 11, exp: "1952-03-11"},
+		{time: "+1952-03-00T00:00:00Z", prec: 10, exp: "1952-03"},
+		{time: "+0927-00-00T00:00:00Z", prec: 9, exp: "927"},
+		{time: "-0044-03-15T00:00:00Z", prec: 11, exp: "44-03-15 BC"},
+	} {
+		got, ok := formatTime(c.time, c.prec)
+		require.True(t, ok)
+		require.Equal(t, c.exp, got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var (
+		searchResp   = providertest.ServeFile(t, "testdata/search_adams.json", "application/json")
+		entitiesResp = providertest.ServeFile(t, "testdata/entities_adams.json", "application/json")
+		labelsResp   = providertest.ServeFile(t, "testdata/labels.json", "application/json")
+	)
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "www.wikidata.org", r.Host)
+		q := r.URL.Query()
+		switch q.Get("action") {
+		case "wbsearchentities":
+			require.Equal(t, "Douglas Adams", q.Get("search"))
+			if q.Get("continue") != "0" {
+				w.Write([]byte(`{"search":[],"success":1}`))
+				return
+			}
+			searchResp(w, r)
+		case "wbgetentities":
+			switch q.Get("ids") {
+			case "Q42|Q21":
+				entitiesResp(w, r)
+			case "Q5|Q3336843", "Q3336843|Q5":
+				labelsResp(w, r)
+			default:
+				t.Errorf("unexpected ids: %q", q.Get("ids"))
+				http.NotFound(w, r)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "Douglas Adams"})
+	defer it.Close()
+	var got []search.Result
+	for it.Next(ctx) {
+		got = append(got, it.Result())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []search.Result{
+		&search.EntityResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://www.wikidata.org/wiki/Q42"),
+				Title: "Douglas Adams",
+				Desc:  "English writer and humorist (1952–2001)",
+			},
+			Type:     "human",
+			Category: "person",
+			Image: &search.Image{
+				URL:   *providertest.MustURL("https://commons.wikimedia.org/wiki/Special:FilePath/Douglas_adams_portrait_cropped.jpg?width=300"),
+				Width: 300,
+			},
+			Attributes: map[string]string{
+				search.AttrBirthDate: "1952-03-11",
+				search.AttrDeathDate: "2001-05-11",
+				search.AttrWebsite:   "http://douglasadams.com/",
+			},
+		},
+		&search.EntityResult{
+			LinkResult: search.LinkResult{
+				URL:   *providertest.MustURL("https://www.wikidata.org/wiki/Q21"),
+				Title: "England",
+				Desc:  "country in north-west Europe, part of the United Kingdom",
+			},
+			Type:     "country of the United Kingdom",
+			Category: "place",
+			Attributes: map[string]string{
+				search.AttrInception:  "927",
+				search.AttrPopulation: "56550138",
+			},
+		},
+	}, got)
+}
@@ -0,0 +1,44 @@
+package wikipedia
+
+import (
+	"context"
+
+	"github.com/dennwc/metasearch/autocomplete"
+)
+
+var (
+	_ autocomplete.Service = (*Service)(nil)
+)
+
+// Suggest returns pages with titles starting with a given text, including their descriptions and thumbnails.
+func (s *Service) Suggest(ctx context.Context, req autocomplete.Request) ([]Page, error) {
+	lang, err := s.editionFor(ctx, req.Lang)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.SearchRaw(ctx, SearchReq{
+		Language: lang,
+		Prefix:   req.Text,
+		Limit:    DefaultLimit,
+		Prop: []Property{
+			PropDescription,
+			PropPageImages,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Query.Pages, nil
+}
+
+func (s *Service) AutoComplete(ctx context.Context, req autocomplete.Request) ([]string, error) {
+	pages, err := s.Suggest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(pages))
+	for _, p := range pages {
+		out = append(out, p.Title)
+	}
+	return out, nil
+}
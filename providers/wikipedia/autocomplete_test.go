@@ -0,0 +1,44 @@
+package wikipedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoComplete(t *testing.T) {
+	prefix := providertest.ServeFile(t, "testdata/prefix_sola.json", "application/json")
+	sitematrix := providertest.ServeFile(t, "testdata/sitematrix.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "meta.wikimedia.org" {
+			sitematrix(w, r)
+			return
+		}
+		require.Equal(t, "de.wikipedia.org", r.Host)
+		q := r.URL.Query()
+		require.Equal(t, "prefixsearch", q.Get("generator"))
+		require.Equal(t, "sola", q.Get("gpssearch"))
+		require.Equal(t, "description|pageimages", q.Get("prop"))
+		prefix(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	req := autocomplete.Request{Text: "sola", Lang: search.MustParseLangCode("de-AT")}
+	list, err := s.AutoComplete(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Solar System", "Solar power", "Solar energy"}, list)
+
+	pages, err := s.Suggest(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, pages, 3)
+	require.Equal(t, "The Sun and objects orbiting it", pages[0].Desc)
+	require.NotNil(t, pages[0].Thumbnail)
+	require.Equal(t, 177, pages[0].Thumbnail.Height)
+}
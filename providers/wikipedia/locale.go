@@ -0,0 +1,177 @@
+package wikipedia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	defaultLanguage = "en"
+	sitematrixURL   = "https://meta.wikimedia.org/w/api.php"
+)
+
+// edition is a language edition of Wikipedia.
+type edition struct {
+	Code string // subdomain of the edition
+	Name string
+	Lang search.LangCode // may be unset, if the code is not a valid language tag
+}
+
+type sitematrixLang struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	LocalName string `json:"localname"`
+	Sites     []struct {
+		URL    string `json:"url"`
+		Code   string `json:"code"`
+		Closed bool   `json:"closed"`
+	} `json:"site"`
+}
+
+func (s *Service) fetchEditions(ctx context.Context) ([]edition, error) {
+	params := make(url.Values)
+	params.Set("action", "sitematrix")
+	params.Set("smtype", "language")
+	params.Set("smlangprop", "code|name|localname|site")
+	params.Set("smsiteprop", "url|code")
+	params.Set("format", "json")
+	params.Set("formatversion", "2")
+
+	var resp struct {
+		Matrix map[string]json.RawMessage `json:"sitematrix"`
+	}
+	if err := s.GetJSON(ctx, sitematrixURL, params, &resp); err != nil {
+		return nil, err
+	}
+	var out []edition
+	for k, data := range resp.Matrix {
+		if k == "count" || k == "specials" {
+			continue
+		}
+		var l sitematrixLang
+		if err := json.Unmarshal(data, &l); err != nil {
+			return nil, fmt.Errorf("cannot parse sitematrix: %v", err)
+		}
+		for _, site := range l.Sites {
+			if site.Code != "wiki" || site.Closed {
+				continue
+			}
+			u, err := url.Parse(site.URL)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse sitematrix: %v", err)
+			}
+			e := edition{
+				Code: strings.TrimSuffix(u.Host, ".wikipedia.org"),
+				Name: l.LocalName,
+			}
+			if e.Name == "" {
+				e.Name = l.Name
+			}
+			if tag, err := search.ParseLangCode(l.Code); err == nil {
+				e.Lang = tag
+			}
+			out = append(out, e)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("cannot parse languages list")
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Code < out[j].Code
+	})
+	return out, nil
+}
+
+// RefreshLanguages reloads the list of Wikipedia editions.
+func (s *Service) RefreshLanguages(ctx context.Context) error {
+	list, err := s.fetchEditions(ctx)
+	if err != nil {
+		return err
+	}
+	tags := make([]language.Tag, 0, len(list))
+	langs := make([]edition, 0, len(list))
+	for _, e := range list {
+		if e.Lang.IsRoot() {
+			continue
+		}
+		tags = append(tags, e.Lang)
+		langs = append(langs, e)
+	}
+	s.mu.Lock()
+	s.editions = list
+	s.langs = langs
+	s.matcher = language.NewMatcher(tags)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) loadEditions(ctx context.Context) error {
+	s.mu.RLock()
+	loaded := s.editions != nil
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	return s.RefreshLanguages(ctx)
+}
+
+// Languages returns a list of languages that have an active Wikipedia edition.
+// The list is cached; see RefreshLanguages.
+func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
+	if err := s.loadEditions(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]search.Language, 0, len(s.langs))
+	for _, e := range s.langs {
+		out = append(out, search.Language{Code: e.Lang, Name: e.Name})
+	}
+	return out, nil
+}
+
+func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
+	return nil, nil // editions are not regional
+}
+
+// ErrNoEdition is returned when there is no Wikipedia edition for the requested language.
+type ErrNoEdition struct {
+	Lang search.LangCode
+}
+
+func (e *ErrNoEdition) Error() string {
+	return fmt.Sprintf("no Wikipedia edition for language %q", e.Lang.String())
+}
+
+// editionFor returns a subdomain of the Wikipedia edition for a given language.
+// If the list of editions cannot be loaded, the base language code is used as-is.
+func (s *Service) editionFor(ctx context.Context, lang search.LangCode) (string, error) {
+	if lang.IsRoot() {
+		return defaultLanguage, nil
+	}
+	base, _ := lang.Base()
+	if err := s.loadEditions(ctx); err != nil {
+		return base.String(), nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, code := range []string{strings.ToLower(lang.String()), base.String()} {
+		for _, e := range s.editions {
+			if e.Code == code {
+				return e.Code, nil
+			}
+		}
+	}
+	if _, i, conf := s.matcher.Match(lang); conf != language.No && i >= 0 && i < len(s.langs) {
+		return s.langs[i].Code, nil
+	}
+	return "", &ErrNoEdition{Lang: lang}
+}
@@ -0,0 +1,86 @@
+package wikipedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func newSitematrixService(t *testing.T) (*Service, *int) {
+	calls := 0
+	sitematrix := providertest.ServeFile(t, "testdata/sitematrix.json", "application/json")
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "meta.wikimedia.org", r.Host)
+		require.Equal(t, "sitematrix", r.URL.Query().Get("action"))
+		calls++
+		sitematrix(w, r)
+	}))
+	s := New()
+	s.SetHTTPClient(cli)
+	return s, &calls
+}
+
+func TestLanguagesSitematrix(t *testing.T) {
+	s, calls := newSitematrixService(t)
+	ctx := context.Background()
+
+	list, err := s.Languages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []search.Language{
+		{Code: search.MustParseLangCode("ab"), Name: "Abkhazian"},
+		{Code: search.MustParseLangCode("de"), Name: "German"},
+		{Code: search.MustParseLangCode("en"), Name: "English"},
+		{Code: search.MustParseLangCode("fr"), Name: "French"},
+		{Code: search.MustParseLangCode("no"), Name: "Norwegian"},
+		{Code: search.MustParseLangCode("zh"), Name: "Chinese"},
+		{Code: search.MustParseLangCode("nan"), Name: "Chinese (Min Nan)"},
+	}, list)
+
+	_, err = s.Languages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, *calls, "expected cached response")
+
+	require.NoError(t, s.RefreshLanguages(ctx))
+	require.Equal(t, 2, *calls)
+}
+
+func TestEditionFor(t *testing.T) {
+	s, _ := newSitematrixService(t)
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		lang string
+		exp  string
+	}{
+		{lang: "", exp: "en"},
+		{lang: "de-DE", exp: "de"},
+		{lang: "nb", exp: "no"},
+		{lang: "nan", exp: "zh-min-nan"},
+		{lang: "zh-TW", exp: "zh"},
+		{lang: "aa", exp: ""},
+		{lang: "ja", exp: ""},
+	} {
+		t.Run(c.lang, func(t *testing.T) {
+			var lang search.LangCode
+			if c.lang != "" {
+				lang = search.MustParseLangCode(c.lang)
+			}
+			got, err := s.editionFor(ctx, lang)
+			if c.exp == "" {
+				require.IsType(t, &ErrNoEdition{}, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.exp, got)
+		})
+	}
+
+	it := s.Search(ctx, search.Request{Query: "Sonne", Lang: search.MustParseLangCode("ja")})
+	defer it.Close()
+	require.False(t, it.Next(ctx))
+	require.IsType(t, &ErrNoEdition{}, it.Err())
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
 
 	"github.com/dennwc/metasearch/providers"
 	"github.com/dennwc/metasearch/search"
@@ -17,7 +21,9 @@ const (
 
 func init() {
 	providers.Register(provName, func(ctx context.Context) (providers.Provider, error) {
-		return New(), nil
+		s := New()
+		s.FullText = true
+		return s, nil
 	})
 }
 
@@ -31,34 +37,46 @@ func New() *Service {
 
 var (
 	DefaultThumbnailSize = 300
+	DefaultLimit         = 10
 )
 
+// maxLimit is the maximal number of intro extracts returned in a single request.
+const maxLimit = 20
+
 type Service struct {
 	providers.HTTPClient
-}
 
-func (*Service) ID() string {
-	return provName
-}
+	// FullText enables full-text search instead of an exact title lookup.
+	FullText bool
 
-func (s *Service) Languages(ctx context.Context) ([]search.Language, error) {
-	return nil, nil // FIXME
+	mu       sync.RWMutex
+	editions []edition
+	langs    []edition // only editions with a valid language code
+	matcher  language.Matcher
 }
 
-func (s *Service) Regions(ctx context.Context) ([]search.Region, error) {
-	return nil, nil // FIXME
+func (*Service) ID() string {
+	return provName
 }
 
 func (s *Service) Search(ctx context.Context, req search.Request) search.ResultIterator {
-	lang, _ := req.Lang.Base()
+	lang, err := s.editionFor(ctx, req.Lang)
+	if err != nil {
+		return &searchIter{err: err}
+	}
 	r := SearchReq{
-		Language: lang.String(),
-		Titles:   req.Query,
+		Language: lang,
 		Prop: []Property{
 			PropExtracts,
 			PropPageImages,
 		},
 	}
+	if s.FullText {
+		r.Search = req.Query
+		r.Limit = DefaultLimit
+	} else {
+		r.Titles = req.Query
+	}
 	return &searchIter{s: s, cur: r}
 }
 
@@ -68,12 +86,20 @@ func (s *Service) ContinueSearch(ctx context.Context, tok search.Token) search.R
 		return &searchIter{err: err}
 	}
 	resp, err := s.SearchRaw(ctx, t.Cur)
-	return &searchIter{s: s, cur: t.Cur, page: resp.Query.Pages, i: t.Off, err: err}
+	if err != nil {
+		return &searchIter{err: err}
+	}
+	return &searchIter{
+		s: s, cur: t.Cur, next: t.Cur.nextPage(resp), fetched: true,
+		page: resp.Query.Pages, i: t.Off,
+	}
 }
 
 type searchIter struct {
-	s   *Service
-	cur SearchReq
+	s       *Service
+	cur     SearchReq
+	next    *SearchReq // nil if there are no more pages
+	fetched bool
 
 	page []Page
 	i    int
@@ -84,15 +110,20 @@ func (it *searchIter) NextPage(ctx context.Context) bool {
 	if it.err != nil || it.s == nil {
 		return false
 	}
-	if len(it.page) != 0 {
-		// FIXME: query the next page
-		return false
+	if it.fetched {
+		it.page = nil
+		if it.next == nil {
+			return false
+		}
+		it.cur = *it.next
 	}
 	resp, err := it.s.SearchRaw(ctx, it.cur)
 	if err != nil {
 		it.err = err
 		return false
 	}
+	it.fetched = true
+	it.next = it.cur.nextPage(resp)
 	it.page = resp.Query.Pages
 	it.i = -1
 	return len(it.page) > 0
@@ -182,6 +213,22 @@ type SearchReq struct {
 	Titles    string     `json:"titles"`
 	Prop      []Property `json:"props"`
 	ThumbSize int        `json:"thumb_size"`
+
+	// Search is a full-text search query. Titles are ignored if it's set.
+	Search string `json:"search,omitempty"`
+	// Prefix searches for pages with titles starting with a given prefix. Titles are ignored if it's set.
+	Prefix string `json:"prefix,omitempty"`
+	Offset int    `json:"off,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// nextPage returns a request for the next page of full-text search results, or nil if there are no more pages.
+func (r SearchReq) nextPage(resp *SearchResp) *SearchReq {
+	if r.Search == "" || resp.Continue == nil || resp.Continue.Offset <= r.Offset {
+		return nil
+	}
+	r.Offset = resp.Continue.Offset
+	return &r
 }
 
 func (r *SearchReq) includesProp(p Property) bool {
@@ -196,8 +243,9 @@ func (r *SearchReq) includesProp(p Property) bool {
 type Property string
 
 const (
-	PropExtracts   = Property("extracts")
-	PropPageImages = Property("pageimages")
+	PropExtracts    = Property("extracts")
+	PropPageImages  = Property("pageimages")
+	PropDescription = Property("description")
 )
 
 type SearchResp struct {
@@ -205,18 +253,25 @@ type SearchResp struct {
 		Text string `json:"warnings"`
 	} `json:"warnings"`
 	BatchComplete bool          `json:"batchcomplete"`
+	Continue      *Continue     `json:"continue,omitempty"`
 	Query         QueryResponse `json:"query"`
 }
 
+type Continue struct {
+	Offset int `json:"gsroffset"`
+}
+
 type QueryResponse struct {
 	Pages []Page `json:"pages"`
 }
 
 type Page struct {
 	ID        int64  `json:"pageid"`
+	Index     int    `json:"index,omitempty"` // rank in full-text search results
 	NS        int    `json:"ns"`
 	Title     string `json:"title"`
 	Extract   string `json:"extract"`
+	Desc      string `json:"description,omitempty"`
 	Thumbnail *Image `json:"thumbnail"`
 	PageImage string `json:"pageimage"`
 }
@@ -229,14 +284,33 @@ type Image struct {
 
 func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, error) {
 	if r.Language == "" {
-		r.Language = "en"
+		r.Language = defaultLanguage
 	}
 	if r.ThumbSize == 0 {
 		r.ThumbSize = DefaultThumbnailSize
 	}
 
 	params := make(url.Values)
-	params.Set("titles", r.Titles)
+	if r.Search != "" {
+		if r.Limit <= 0 || r.Limit > maxLimit {
+			r.Limit = maxLimit
+		}
+		params.Set("generator", "search")
+		params.Set("gsrsearch", r.Search)
+		params.Set("gsrnamespace", "0")
+		params.Set("gsrlimit", strconv.Itoa(r.Limit))
+		params.Set("gsroffset", strconv.Itoa(r.Offset))
+	} else if r.Prefix != "" {
+		if r.Limit <= 0 || r.Limit > maxLimit {
+			r.Limit = maxLimit
+		}
+		params.Set("generator", "prefixsearch")
+		params.Set("gpssearch", r.Prefix)
+		params.Set("gpsnamespace", "0")
+		params.Set("gpslimit", strconv.Itoa(r.Limit))
+	} else {
+		params.Set("titles", r.Titles)
+	}
 	params.Set("action", "query")
 	params.Set("format", "json")
 	params.Set("formatversion", "2")
@@ -250,9 +324,15 @@ func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, erro
 	if r.includesProp(PropExtracts) {
 		params.Set("exintro", "")
 		params.Set("explaintext", "")
+		if r.Search != "" || r.Prefix != "" {
+			params.Set("exlimit", "max")
+		}
 	}
 	if r.includesProp(PropPageImages) {
 		params.Set("pithumbsize", strconv.Itoa(r.ThumbSize))
+		if r.Search != "" || r.Prefix != "" {
+			params.Set("pilimit", "max")
+		}
 	}
 	params.Set("redirects", "")
 
@@ -261,5 +341,11 @@ func (s *Service) SearchRaw(ctx context.Context, r SearchReq) (*SearchResp, erro
 	if err != nil {
 		return nil, err
 	}
+	if r.Search != "" || r.Prefix != "" {
+		// pages are returned in arbitrary order
+		sort.SliceStable(out.Query.Pages, func(i, j int) bool {
+			return out.Query.Pages[i].Index < out.Query.Pages[j].Index
+		})
+	}
 	return &out, nil
 }
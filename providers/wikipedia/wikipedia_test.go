@@ -2,22 +2,15 @@ package wikipedia
 
 import (
 	"context"
-	"net/url"
+	"net/http"
 	"testing"
 
+	"github.com/dennwc/metasearch/providers/providertest"
 	"github.com/dennwc/metasearch/search"
 	"github.com/dennwc/metasearch/search/searchtest"
 	"github.com/stretchr/testify/require"
 )
 
-func mustURL(s string) url.URL {
-	u, err := url.Parse(s)
-	if err != nil {
-		panic(err)
-	}
-	return *u
-}
-
 func TestWiki(t *testing.T) {
 	s := New()
 	ctx := context.Background()
@@ -66,12 +59,12 @@ The solar wind, a stream of charged particles flowing outwards from the Sun, cre
 	require.Equal(t, []search.Result{
 		&search.EntityResult{
 			LinkResult: search.LinkResult{
-				URL:   mustURL("https://en.wikipedia.org/wiki/Solar_System"),
+				URL:   *providertest.MustURL("https://en.wikipedia.org/wiki/Solar_System"),
 				Title: "Solar System",
 				Desc:  extract,
 			},
 			Image: &search.Image{
-				URL:    mustURL("https://upload.wikimedia.org/wikipedia/commons/thumb/c/cb/Planets2013.svg/300px-Planets2013.svg.png"),
+				URL:    *providertest.MustURL("https://upload.wikimedia.org/wikipedia/commons/thumb/c/cb/Planets2013.svg/300px-Planets2013.svg.png"),
 				Width:  300,
 				Height: 177,
 			},
@@ -83,3 +76,62 @@ func TestWikipedia(t *testing.T) {
 	s := New()
 	searchtest.RunSearchTest(t, s, nil)
 }
+
+func TestFullTextSearch(t *testing.T) {
+	pages := map[string]http.HandlerFunc{
+		"0": providertest.ServeFile(t, "testdata/search_solar_0.json", "application/json"),
+		"3": providertest.ServeFile(t, "testdata/search_solar_3.json", "application/json"),
+	}
+	requests := 0
+	_, cli := providertest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "en.wikipedia.org", r.Host)
+		q := r.URL.Query()
+		require.Equal(t, "search", q.Get("generator"))
+		require.Equal(t, "solar", q.Get("gsrsearch"))
+		require.Equal(t, "extracts|pageimages", q.Get("prop"))
+		h, ok := pages[q.Get("gsroffset")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		h(w, r)
+	}))
+	s := New()
+	s.FullText = true
+	s.SetHTTPClient(cli)
+	ctx := context.Background()
+
+	it := s.Search(ctx, search.Request{Query: "solar"})
+	defer it.Close()
+
+	var (
+		got []string
+		tok search.Token
+	)
+	for it.Next(ctx) {
+		r := it.Result()
+		require.IsType(t, &search.EntityResult{}, r)
+		got = append(got, r.GetTitle())
+		if len(got) == 2 {
+			tok = it.Token()
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{
+		"Solar System", "Sun", "Solar energy",
+		"Solar wind", "Solar power",
+	}, got)
+	require.Equal(t, 2, requests)
+
+	it2 := s.ContinueSearch(ctx, tok)
+	defer it2.Close()
+	got = nil
+	for it2.Next(ctx) {
+		got = append(got, it2.Result().GetTitle())
+	}
+	require.NoError(t, it2.Err())
+	require.Equal(t, []string{
+		"Solar energy", "Solar wind", "Solar power",
+	}, got)
+}
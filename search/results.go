@@ -2,6 +2,7 @@ package search
 
 import (
 	"net/url"
+	"time"
 )
 
 type LinkResult struct {
@@ -77,8 +78,171 @@ type EntityResult struct {
 	Type     string
 	Category string
 	Image    *Image
+	// Attributes are key facts about the entity, such as birth date or population.
+	// Values are formatted for display.
+	Attributes map[string]string
 }
 
 func (r *EntityResult) GetThumbnail() *Image {
 	return r.Image
 }
+
+// Well-known keys of EntityResult attributes.
+const (
+	AttrBirthDate  = "birth_date"
+	AttrDeathDate  = "death_date"
+	AttrInception  = "inception"
+	AttrPopulation = "population"
+	AttrWebsite    = "website"
+)
+
+// RepoResult is a source code repository.
+type RepoResult struct {
+	LinkResult
+	// Name is a full name of the repository, e.g. "owner/repo".
+	Name     string
+	Stars    int
+	Forks    int
+	Language string
+	Topics   []string
+	Updated  time.Time
+}
+
+// IssueResult is an issue or a pull request in a repository.
+type IssueResult struct {
+	LinkResult
+	// Repo is a full name of the repository, e.g. "owner/repo".
+	Repo        string
+	State       string
+	PullRequest bool
+	Comments    int
+	Labels      []string
+	Updated     time.Time
+}
+
+// QAResult is a question on a Q&A site.
+type QAResult struct {
+	LinkResult
+	Score    int
+	Answers  int
+	Accepted bool // has an accepted answer
+	Tags     []string
+	Updated  time.Time
+}
+
+// PaperResult is a scientific paper or a preprint.
+type PaperResult struct {
+	LinkResult
+	Authors    []string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+	PDF        *url.URL
+}
+
+// DiscussionResult is a discussion thread about a topic or a link. URL points to the thread.
+type DiscussionResult struct {
+	LinkResult
+	// Link is the URL being discussed, if any.
+	Link *url.URL
+	// Forum is a name of the site or the community, e.g. "Hacker News" or "r/golang".
+	Forum    string
+	Author   string
+	Points   int
+	Comments int
+	Created  time.Time
+}
+
+// PackageResult is a software package or a module.
+type PackageResult struct {
+	LinkResult
+	// Name is a package name or an import path.
+	Name      string
+	Version   string
+	License   string
+	Imports   int // number of known importers
+	Downloads int // recent downloads, as reported by the registry
+	// Repository is the URL of the source code repository, if known.
+	Repository *url.URL
+	Published  time.Time
+}
+
+// PlaceResult is a geographic place, such as a city, an address or a point of interest.
+type PlaceResult struct {
+	LinkResult
+	Lat, Lon float64
+	Bounds   *BoundingBox
+	// Address components, e.g. "city", "road" or "country".
+	Address map[string]string
+	// Category and Type of the place, e.g. "amenity" and "cafe".
+	Category string
+	Type     string
+	// OSMType and OSMID identify the place in OpenStreetMap.
+	OSMType string
+	OSMID   int64
+}
+
+// BoundingBox is a geographic area defined by latitudes and longitudes.
+type BoundingBox struct {
+	South, North float64
+	West, East   float64
+}
+
+// ArchiveItemResult is an item stored in a digital library, such as a book, a recording or a collection.
+type ArchiveItemResult struct {
+	LinkResult
+	Identifier string
+	// MediaType is a kind of the item, e.g. "texts", "movies" or "audio".
+	MediaType string
+	Creators  []string
+	Date      time.Time
+	Downloads int
+}
+
+// Snapshot is an archived copy of a web page.
+type Snapshot struct {
+	// URL of the archived copy.
+	URL      *url.URL
+	Original *url.URL
+	Time     time.Time
+	// Status is an HTTP status code of the archived response.
+	Status int
+}
+
+// ArchivedResult is a search result with a link to its archived copy.
+type ArchivedResult struct {
+	Result
+	// Snapshot is nil if the page was never archived.
+	Snapshot *Snapshot
+}
+
+// HistoryResult is a bookmark or a page from the browsing history.
+type HistoryResult struct {
+	LinkResult
+	Visits     int
+	LastVisit  time.Time
+	Bookmarked bool
+	// Folder is a slash-separated path of the bookmark folder.
+	Folder string
+	Tags   []string
+}
+
+// AnswerResult is a direct answer to the query, such as a result of a calculation or a unit conversion.
+// Title contains the answer together with the question, e.g. "12 ft = 3.6576 m". URL may be empty.
+type AnswerResult struct {
+	LinkResult
+	// Kind of the answer, e.g. "calc", "unit", "time" or "encoding".
+	Kind string
+	// Value is the answer itself, e.g. "3.6576 m".
+	Value string
+}
+
+// FederatedResult is a result returned by a remote metasearch instance.
+type FederatedResult struct {
+	Result
+	// Node is the ID of the remote instance.
+	Node string
+	// Source is a chain of service IDs that returned the result on the remote instance,
+	// e.g. ["google"], or ["remote:eu.example.com", "bing"] if the remote instance federates as well.
+	Source []string
+}
@@ -3,6 +3,7 @@ package search
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/dennwc/metasearch/base"
 )
@@ -20,6 +21,28 @@ type Service interface {
 	Searcher
 }
 
+// DiscussionFinder is implemented by services that can find discussions of a given web page.
+type DiscussionFinder interface {
+	base.Provider
+	Discussions(ctx context.Context, u *url.URL) ([]*DiscussionResult, error)
+}
+
+// SnapshotFinder is implemented by services that store archived copies of web pages.
+type SnapshotFinder interface {
+	base.Provider
+	// Snapshot returns an archived copy of the page closest to a given time, or the latest one if the time is zero.
+	// It returns nil if the page was never archived.
+	Snapshot(ctx context.Context, u *url.URL, at time.Time) (*Snapshot, error)
+}
+
+// AnswerService is implemented by services that answer queries directly, for example calculators.
+// Results of these services are returned by the Engine before any other results.
+type AnswerService interface {
+	Service
+	// Answer returns an answer to the query, or nil if the query cannot be answered.
+	Answer(ctx context.Context, req Request) (*AnswerResult, error)
+}
+
 type Request struct {
 	Query  string
 	Lang   LangCode
@@ -47,6 +70,13 @@ func (Empty) Token() Token {
 	return nil
 }
 
+// SourceIterator is implemented by iterators that merge results of multiple services.
+type SourceIterator interface {
+	ResultIterator
+	// Source returns the ID of the service that returned the current result.
+	Source() string
+}
+
 type Result interface {
 	GetURL() *url.URL
 	GetTitle() string
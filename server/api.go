@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dennwc/metasearch/search"
+)
+
+const (
+	// SearchPath is the path of the search API endpoint.
+	SearchPath = "/api/search"
+	// CompletePath is the path of the auto-complete API endpoint.
+	CompletePath = "/api/complete"
+
+	// HeaderVia lists IDs of metasearch nodes that already handled the request, comma-separated.
+	// It is used to detect federation loops.
+	HeaderVia = "X-Metasearch-Via"
+)
+
+// SearchResponse is a page of search results returned by the search endpoint.
+type SearchResponse struct {
+	// Node is the ID of the node that served the request.
+	Node    string   `json:"node"`
+	Results []Result `json:"results"`
+	// Token continues the search after the last result. It is empty if there are no more results.
+	// It is signed by the node and cannot be used with other nodes.
+	Token search.Token `json:"token,omitempty"`
+}
+
+// Result is a search result of any type.
+type Result struct {
+	// Type of the result, e.g. "link" or "package".
+	Type string `json:"type"`
+	// Source is a chain of service IDs that returned the result.
+	Source []string        `json:"source,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// ErrorResponse is returned by API endpoints in case of an error.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// resultTypes are result types that can be sent over the API.
+var resultTypes = map[string]func() search.Result{
+	"link":         func() search.Result { return new(search.LinkResult) },
+	"image":        func() search.Result { return new(search.ImageResult) },
+	"video":        func() search.Result { return new(search.VideoResult) },
+	"entity":       func() search.Result { return new(search.EntityResult) },
+	"repo":         func() search.Result { return new(search.RepoResult) },
+	"issue":        func() search.Result { return new(search.IssueResult) },
+	"qa":           func() search.Result { return new(search.QAResult) },
+	"paper":        func() search.Result { return new(search.PaperResult) },
+	"discussion":   func() search.Result { return new(search.DiscussionResult) },
+	"package":      func() search.Result { return new(search.PackageResult) },
+	"place":        func() search.Result { return new(search.PlaceResult) },
+	"archive_item": func() search.Result { return new(search.ArchiveItemResult) },
+	"history":      func() search.Result { return new(search.HistoryResult) },
+	"answer":       func() search.Result { return new(search.AnswerResult) },
+}
+
+var resultNames = make(map[reflect.Type]string)
+
+func init() {
+	for name, fnc := range resultTypes {
+		resultNames[reflect.TypeOf(fnc())] = name
+	}
+}
+
+// EncodeResult encodes a search result returned by a given source.
+// Results of unknown types are encoded as links.
+func EncodeResult(r search.Result, source ...string) (*Result, error) {
+	if r == nil {
+		return nil, fmt.Errorf("nil result")
+	}
+	switch rt := r.(type) {
+	case *search.FederatedResult:
+		source = append(source, rt.Source...)
+		r = rt.Result
+	case *search.ArchivedResult:
+		r = rt.Result
+	}
+	if r == nil {
+		return nil, fmt.Errorf("nil result")
+	}
+	name, ok := resultNames[reflect.TypeOf(r)]
+	if !ok {
+		name = "link"
+		lr := &search.LinkResult{Title: r.GetTitle(), Desc: r.GetDesc()}
+		if u := r.GetURL(); u != nil {
+			lr.URL = *u
+		}
+		r = lr
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Type: name, Source: source, Data: data}, nil
+}
+
+// DecodeResult decodes a search result. It does not return the source of the result.
+func DecodeResult(r *Result) (search.Result, error) {
+	fnc, ok := resultTypes[r.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported result type: %q", r.Type)
+	}
+	out := fnc()
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		return nil, fmt.Errorf("%s result: %v", r.Type, err)
+	}
+	return out, nil
+}
+
+type viaKey struct{}
+
+// WithVia returns a context with a list of nodes that handled the request.
+// Remote providers pass this list to other nodes in HeaderVia.
+func WithVia(ctx context.Context, via []string) context.Context {
+	return context.WithValue(ctx, viaKey{}, via)
+}
+
+// Via returns a list of nodes that handled the request.
+func Via(ctx context.Context) []string {
+	via, _ := ctx.Value(viaKey{}).([]string)
+	return via
+}
+
+// ParseVia parses the value of HeaderVia.
+func ParseVia(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
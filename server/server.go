@@ -0,0 +1,274 @@
+// Package server provides an HTTP JSON API for a metasearch engine.
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dennwc/metasearch/autocomplete"
+	"github.com/dennwc/metasearch/search"
+)
+
+var (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+	// DefaultMaxHops limits the number of nodes a request can pass through.
+	DefaultMaxHops = 8
+)
+
+// New creates an API server for a search engine with a given node ID.
+// If the engine implements autocomplete.Service, the auto-complete endpoint is enabled as well.
+// If the node ID is empty, a random one is generated.
+func New(node string, s search.Searcher) *Server {
+	if node == "" {
+		node = hex.EncodeToString(randBytes(8))
+	}
+	srv := &Server{
+		Node:    node,
+		Key:     randBytes(sha256.Size),
+		MaxHops: DefaultMaxHops,
+		search:  s,
+		mux:     http.NewServeMux(),
+	}
+	srv.autoc, _ = s.(autocomplete.Service)
+	srv.mux.HandleFunc(SearchPath, srv.handleSearch)
+	srv.mux.HandleFunc(CompletePath, srv.handleComplete)
+	return srv
+}
+
+func randBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+type Server struct {
+	// Node is the ID of this node. It must be unique among federated nodes.
+	Node string
+	// Key signs continuation tokens returned to clients. It is random by default,
+	// thus tokens are not accepted after a restart.
+	Key     []byte
+	MaxHops int
+
+	search search.Searcher
+	autoc  autocomplete.Service
+	mux    *http.ServeMux
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, ErrorResponse{Error: err.Error()})
+}
+
+// enter checks the request for federation loops and adds this node to the list of nodes that handled it.
+func (s *Server) enter(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return nil, false
+	}
+	via := ParseVia(r.Header.Get(HeaderVia))
+	for _, id := range via {
+		if id == s.Node {
+			writeError(w, http.StatusLoopDetected, fmt.Errorf("loop detected: %s", strings.Join(via, ", ")))
+			return nil, false
+		}
+	}
+	if s.MaxHops > 0 && len(via) >= s.MaxHops {
+		writeError(w, http.StatusLoopDetected, fmt.Errorf("too many hops: %d", len(via)))
+		return nil, false
+	}
+	via = append(via, s.Node)
+	return r.WithContext(WithVia(r.Context(), via)), true
+}
+
+func (s *Server) tokenMAC(tok []byte) []byte {
+	m := hmac.New(sha256.New, s.Key)
+	m.Write(tok)
+	return m.Sum(nil)
+}
+
+// signToken prepends an HMAC of the token, so clients cannot alter it.
+// Tokens are passed to providers as is and may contain URLs or other data that must not be forged.
+func (s *Server) signToken(tok search.Token) search.Token {
+	return search.Token(append(s.tokenMAC(tok), tok...))
+}
+
+// verifyToken checks the signature of the token and returns the original token.
+func (s *Server) verifyToken(data []byte) (search.Token, error) {
+	if len(data) <= sha256.Size {
+		return nil, fmt.Errorf("token is too short")
+	}
+	mac, tok := data[:sha256.Size], data[sha256.Size:]
+	if !hmac.Equal(mac, s.tokenMAC(tok)) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+	return search.Token(tok), nil
+}
+
+func parseRequest(r *http.Request) (search.Request, error) {
+	q := r.URL.Query()
+	req := search.Request{Query: q.Get("q")}
+	if v := q.Get("lang"); v != "" {
+		lang, err := search.ParseLangCode(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid language: %q", v)
+		}
+		req.Lang = lang
+	}
+	if v := q.Get("region"); v != "" {
+		region, err := search.ParseRegionCode(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid region: %q", v)
+		}
+		req.Region = region
+	}
+	if v := q.Get("safe"); v != "" {
+		safe, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid safe flag: %q", v)
+		}
+		req.Safe = safe
+	}
+	return req, nil
+}
+
+// handleSearch returns a page of search results. Parameters:
+//
+//	q      - search query
+//	lang   - language code, e.g. "en-US"
+//	region - region code, e.g. "US"
+//	safe   - safe search flag
+//	n      - page size
+//	token  - continuation token from the previous page, base64 encoded (URL alphabet, no padding);
+//	         tokens are signed by the node, thus only tokens issued by the same node are accepted
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	r, ok := s.enter(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	n := DefaultPageSize
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		n, err = strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid page size: %q", v))
+			return
+		}
+		if n > MaxPageSize {
+			n = MaxPageSize
+		}
+	}
+	var it search.ResultIterator
+	if v := r.URL.Query().Get("token"); v != "" {
+		data, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid token: %v", err))
+			return
+		}
+		tok, err := s.verifyToken(data)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid token: %v", err))
+			return
+		}
+		it = s.search.ContinueSearch(ctx, tok)
+	} else {
+		req, err := parseRequest(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if strings.TrimSpace(req.Query) == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("query must be set"))
+			return
+		}
+		it = s.search.Search(ctx, req)
+	}
+	defer it.Close()
+	src, _ := it.(search.SourceIterator)
+
+	resp := SearchResponse{Node: s.Node, Results: []Result{}}
+	for len(resp.Results) < n && it.Next(ctx) {
+		var source []string
+		if src != nil {
+			source = []string{src.Source()}
+		}
+		r := it.Result()
+		if r == nil {
+			// the provider failed to convert the result, Err will report it
+			continue
+		}
+		res, err := EncodeResult(r, source...)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		resp.Results = append(resp.Results, *res)
+	}
+	if err := it.Err(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(resp.Results) == n {
+		tok := it.Token()
+		if err := it.Err(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if len(tok) != 0 {
+			// the iterator may have no token when it ends exactly on the page boundary
+			resp.Token = s.signToken(tok)
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleComplete returns a list of suggestions for a query. Parameters are the same as for search.
+func (s *Server) handleComplete(w http.ResponseWriter, r *http.Request) {
+	if s.autoc == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("auto-complete is not supported"))
+		return
+	}
+	r, ok := s.enter(w, r)
+	if !ok {
+		return
+	}
+	req, err := parseRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	list, err := s.autoc.AutoComplete(r.Context(), autocomplete.Request{
+		Text: req.Query, Lang: req.Lang, Region: req.Region,
+	})
+	if err != nil && len(list) == 0 {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if list == nil {
+		list = []string{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
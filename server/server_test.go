@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dennwc/metasearch/providers/providertest"
+	"github.com/dennwc/metasearch/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeResult(t *testing.T) {
+	pkg := &search.PackageResult{
+		LinkResult: search.LinkResult{URL: *providertest.MustURL("https://pkg.go.dev/example.com/mod"), Title: "mod", Desc: "A module"},
+		Name:       "example.com/mod",
+		Version:    "v1.2.3",
+		Repository: providertest.MustURL("https://github.com/example/mod"),
+		Published:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	r, err := EncodeResult(pkg, "godoc")
+	require.NoError(t, err)
+	require.Equal(t, "package", r.Type)
+	require.Equal(t, []string{"godoc"}, r.Source)
+
+	// results pass through the JSON API
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	var r2 Result
+	require.NoError(t, json.Unmarshal(data, &r2))
+	got, err := DecodeResult(&r2)
+	require.NoError(t, err)
+	require.Equal(t, pkg, got)
+
+	// federated results extend the source
+	r, err = EncodeResult(&search.FederatedResult{Result: pkg, Node: "eu", Source: []string{"godoc"}}, "remote:eu")
+	require.NoError(t, err)
+	require.Equal(t, "package", r.Type)
+	require.Equal(t, []string{"remote:eu", "godoc"}, r.Source)
+
+	_, err = DecodeResult(&Result{Type: "unknown", Data: []byte("{}")})
+	require.Error(t, err)
+
+	_, err = EncodeResult(nil)
+	require.Error(t, err)
+	_, err = EncodeResult(&search.FederatedResult{Node: "eu"})
+	require.Error(t, err)
+}
+
+type fakeSearcher struct {
+	last context.Context
+}
+
+func (s *fakeSearcher) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	s.last = ctx
+	return search.Empty{}
+}
+
+func (s *fakeSearcher) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	s.last = ctx
+	return search.Empty{}
+}
+
+func TestVia(t *testing.T) {
+	fs := &fakeSearcher{}
+	srv := New("a", fs)
+	srv.MaxHops = 3
+
+	do := func(path, via string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if via != "" {
+			req.Header.Set(HeaderVia, via)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(SearchPath+"?q=test", "b, c")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp SearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "a", resp.Node)
+	require.Empty(t, resp.Results)
+	require.Equal(t, []string{"b", "c", "a"}, Via(fs.last))
+
+	w = do(SearchPath+"?q=test", "b, a")
+	require.Equal(t, http.StatusLoopDetected, w.Code)
+
+	w = do(SearchPath+"?q=test", "b,c,d")
+	require.Equal(t, http.StatusLoopDetected, w.Code)
+
+	w = do(SearchPath, "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(SearchPath+"?token=%21", "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the searcher does not support auto-complete
+	w = do(CompletePath+"?q=test", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// listSearcher returns a fixed list of results. Tokens are offsets in the list, there is no token after the last result.
+type listSearcher struct {
+	results []search.Result
+	toks    []string
+}
+
+func (s *listSearcher) Search(ctx context.Context, req search.Request) search.ResultIterator {
+	return &listIter{list: s.results, i: -1}
+}
+
+func (s *listSearcher) ContinueSearch(ctx context.Context, tok search.Token) search.ResultIterator {
+	s.toks = append(s.toks, string(tok))
+	i, err := strconv.Atoi(string(tok))
+	if err != nil {
+		return search.Empty{}
+	}
+	return &listIter{list: s.results, i: i}
+}
+
+type listIter struct {
+	search.Empty
+	list []search.Result
+	i    int
+}
+
+func (it *listIter) Next(ctx context.Context) bool {
+	if it.i+1 >= len(it.list) {
+		return false
+	}
+	it.i++
+	return true
+}
+
+func (it *listIter) Result() search.Result {
+	return it.list[it.i]
+}
+
+func (it *listIter) Token() search.Token {
+	if it.i+1 >= len(it.list) {
+		return nil
+	}
+	return search.Token(strconv.Itoa(it.i))
+}
+
+func TestSearchToken(t *testing.T) {
+	link := func(s string) search.Result {
+		return &search.LinkResult{URL: *providertest.MustURL("https://example.com/" + s), Title: s}
+	}
+	ls := &listSearcher{results: []search.Result{link("a"), nil, link("b"), link("c")}}
+	srv := New("a", ls)
+
+	do := func(query string) (int, *SearchResponse) {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", SearchPath+"?"+query, nil))
+		var resp SearchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, &resp
+	}
+	titles := func(resp *SearchResponse) []string {
+		var out []string
+		for _, r := range resp.Results {
+			res, err := DecodeResult(&r)
+			require.NoError(t, err)
+			out = append(out, res.GetTitle())
+		}
+		return out
+	}
+
+	// nil results are skipped
+	code, resp := do("q=test&n=2")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"a", "b"}, titles(resp))
+	require.NotEmpty(t, resp.Token)
+
+	code, resp = do("n=2&token=" + base64.RawURLEncoding.EncodeToString(resp.Token))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"c"}, titles(resp))
+	require.Equal(t, []string{"2"}, ls.toks)
+
+	// no token is returned when the page ends exactly at the last result
+	code, resp = do("q=test&n=3")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"a", "b", "c"}, titles(resp))
+	require.Empty(t, resp.Token)
+
+	// unsigned and forged tokens never reach the searcher
+	for _, tok := range [][]byte{
+		[]byte("0"),
+		append(make([]byte, 32), '0'),
+		append(srv.tokenMAC([]byte("2")), '0'),
+	} {
+		code, _ = do("token=" + base64.RawURLEncoding.EncodeToString(tok))
+		require.Equal(t, http.StatusBadRequest, code)
+	}
+	require.Equal(t, []string{"2"}, ls.toks)
+
+	// tokens of other nodes are not accepted
+	other := New("b", ls)
+	w := httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest("GET", SearchPath+"?q=test&n=2", nil))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	code, _ = do("token=" + base64.RawURLEncoding.EncodeToString(resp.Token))
+	require.Equal(t, http.StatusBadRequest, code)
+}